@@ -5,4 +5,5 @@ go 1.23.4
 require (
 	github.com/gorilla/mux v1.8.1
 	github.com/joho/godotenv v1.5.1
+	golang.org/x/text v0.21.0
 )
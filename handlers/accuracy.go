@@ -0,0 +1,327 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 선행 시간 구간 (시간). 단기예보는 최대 3일 앞까지 나옵니다.
+var leadBuckets = [][2]int{{0, 6}, {6, 12}, {12, 24}, {24, 48}, {48, 72}, {72, 120}}
+
+// parsePrecipAmount는 기상청 강수량 값("강수없음", "1mm 미만", "3.5", "30.0~50.0mm", "50.0mm 이상")을 mm로 바꿉니다.
+func parsePrecipAmount(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "" || s == "-":
+		return 0, false
+	case s == "강수없음" || s == "적설없음":
+		return 0, true
+	case strings.Contains(s, "미만"):
+		// "1mm 미만", "1cm 미만": 양은 적지만 강수는 있었음
+		return 0.5, true
+	}
+	// 범위나 "이상"은 아래쪽 값을 씁니다.
+	s = strings.TrimSpace(strings.TrimSuffix(s, "이상"))
+	if i := strings.Index(s, "~"); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "mm"), "cm")
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
+// 관측에서 강수가 있었는지: 1시간 강수량(RN1)이 0보다 크거나 강수 형태(PTY)가 있으면 강수로 봅니다.
+func observedPrecip(values map[string]string) (bool, bool) {
+	rn1, okRain := parsePrecipAmount(values["RN1"])
+	pty, okPty := values["PTY"]
+	if !okRain && !okPty {
+		return false, false
+	}
+	return rn1 > 0 || (okPty && pty != "0"), true
+}
+
+type accuracyAccumulator struct {
+	tmpAbs, tmpSum       float64
+	tmpN                 int
+	brierSum             float64
+	popN                 int
+	hits, misses, fa, cn int
+}
+
+func (a *accuracyAccumulator) stats(label string, minHours, maxHours int) models.LeadTimeStats {
+	ratio := func(num, den float64) *float64 {
+		if den == 0 {
+			return nil
+		}
+		v := math.Round(num/den*1000) / 1000
+		return &v
+	}
+	return models.LeadTimeStats{
+		Label:            label,
+		MinHours:         minHours,
+		MaxHours:         maxHours,
+		TmpSamples:       a.tmpN,
+		TmpMAE:           ratio(a.tmpAbs, float64(a.tmpN)),
+		TmpBias:          ratio(a.tmpSum, float64(a.tmpN)),
+		PopSamples:       a.popN,
+		Brier:            ratio(a.brierSum, float64(a.popN)),
+		Hits:             a.hits,
+		Misses:           a.misses,
+		FalseAlarms:      a.fa,
+		CorrectNegatives: a.cn,
+		HitRate:          ratio(float64(a.hits), float64(a.hits+a.misses)),
+		FalseAlarmRatio:  ratio(float64(a.fa), float64(a.hits+a.fa)),
+	}
+}
+
+// add는 예보 한 칸과 그 시각의 관측을 비교해 누적합니다.
+func (a *accuracyAccumulator) add(forecast, observed map[string]string) {
+	if f, err := strconv.ParseFloat(forecast["TMP"], 64); err == nil {
+		if o, err := strconv.ParseFloat(observed["T1H"], 64); err == nil {
+			a.tmpAbs += math.Abs(f - o)
+			a.tmpSum += f - o
+			a.tmpN++
+		}
+	}
+
+	rained, ok := observedPrecip(observed)
+	if !ok {
+		return
+	}
+	outcome := 0.0
+	if rained {
+		outcome = 1
+	}
+	if pop, err := strconv.ParseFloat(forecast["POP"], 64); err == nil {
+		a.brierSum += math.Pow(pop/100-outcome, 2)
+		a.popN++
+	}
+	pty, ok := forecast["PTY"]
+	if !ok {
+		return
+	}
+	predicted := pty != "0"
+	switch {
+	case predicted && rained:
+		a.hits++
+	case !predicted && rained:
+		a.misses++
+	case predicted && !rained:
+		a.fa++
+	default:
+		a.cn++
+	}
+}
+
+// buildAccuracyReport는 최근 days일 동안 예보 시각이 지난 예보를 관측과 비교합니다.
+func buildAccuracyReport(grid string, days int, now time.Time) (models.AccuracyReport, error) {
+	to := now.In(seoul)
+	from := to.AddDate(0, 0, -days)
+	report := models.AccuracyReport{Grid: grid, From: from, To: to}
+
+	observations, err := observationsBetween(grid, from, to)
+	if err != nil {
+		return report, err
+	}
+	observedAt := make(map[int64]map[string]string, len(observations))
+	for _, o := range observations {
+		observedAt[o.Time.Unix()] = o.Values
+	}
+
+	overall := &accuracyAccumulator{}
+	buckets := make([]accuracyAccumulator, len(leadBuckets))
+	type calibrationSum struct {
+		n             int
+		forecast, obs float64
+	}
+	calibration := make([]calibrationSum, 10)
+
+	// 예보 시각이 기간 안에 들어오려면 발표는 최대 3일 더 앞설 수 있습니다.
+	err = scanForecasts(grid, from.AddDate(0, 0, -3), to, func(record models.ForecastRecord) {
+		if record.FcstTime.Before(from) || record.FcstTime.After(to) {
+			return
+		}
+		observed, ok := observedAt[record.FcstTime.Unix()]
+		if !ok {
+			return
+		}
+		lead := int(record.FcstTime.Sub(record.BaseTime).Hours())
+		for i, bucket := range leadBuckets {
+			if lead >= bucket[0] && lead < bucket[1] {
+				buckets[i].add(record.Values, observed)
+			}
+		}
+		overall.add(record.Values, observed)
+
+		pop, err := strconv.ParseFloat(record.Values["POP"], 64)
+		rained, ok := observedPrecip(observed)
+		if err != nil || !ok {
+			return
+		}
+		bin := int(pop / 10)
+		if bin > 9 {
+			bin = 9
+		}
+		calibration[bin].n++
+		calibration[bin].forecast += pop
+		if rained {
+			calibration[bin].obs += 100
+		}
+	})
+	if err != nil {
+		return report, err
+	}
+
+	report.Overall = overall.stats("전체", 0, 0)
+	for i, bucket := range leadBuckets {
+		report.Leads = append(report.Leads, buckets[i].stats(fmt.Sprintf("%d~%d시간", bucket[0], bucket[1]), bucket[0], bucket[1]))
+	}
+	for i, c := range calibration {
+		bin := models.CalibrationBin{From: i * 10, To: i*10 + 10, Samples: c.n}
+		if c.n > 0 {
+			mean := math.Round(c.forecast/float64(c.n)*10) / 10
+			freq := math.Round(c.obs/float64(c.n)*10) / 10
+			bin.MeanForecast, bin.ObservedFrequency = &mean, &freq
+		}
+		report.Calibration = append(report.Calibration, bin)
+	}
+	return report, nil
+}
+
+// accuracyParams는 쿼리의 grid와 days를 읽습니다. 잘못된 값이면 오류(400)를 반환합니다.
+// 예보 이력은 보관 기간만큼만 남아 있으므로 days는 보관 일수를 넘지 않게 줄입니다.
+func accuracyParams(r *http.Request) (string, int, error) {
+	grid, err := gridFromRequest(r)
+	if err != nil {
+		return "", 0, err
+	}
+	days := 14
+	if v := r.URL.Query().Get("days"); v != "" {
+		if days, err = strconv.Atoi(v); err != nil || days <= 0 {
+			return "", 0, fmt.Errorf("days는 1 이상의 정수여야 합니다")
+		}
+	}
+	if limit := forecastHistory.retention(); days > limit {
+		days = limit
+	}
+	return grid, days, nil
+}
+
+// accuracyFromRequest는 쿼리로 정확도를 계산합니다. 잘못된 쿼리면 400, 계산에 실패하면 500으로 응답하고 false를 반환합니다.
+func accuracyFromRequest(w http.ResponseWriter, r *http.Request) (models.AccuracyReport, bool) {
+	grid, days, err := accuracyParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return models.AccuracyReport{}, false
+	}
+	report, err := buildAccuracyReport(grid, days, time.Now())
+	if err != nil {
+		log.Printf("정확도 계산 실패: %v", err)
+		http.Error(w, "정확도를 계산할 수 없습니다.", http.StatusInternalServerError)
+		return models.AccuracyReport{}, false
+	}
+	return report, true
+}
+
+// GetAccuracyJSON은 선행 시간별 예보 정확도를 JSON으로 반환합니다. (?days=14&grid=nx,ny)
+func GetAccuracyJSON(w http.ResponseWriter, r *http.Request) {
+	report, ok := accuracyFromRequest(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func formatStat(v *float64, format string) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf(format, *v)
+}
+
+// 정확도 표 한 줄
+type leadRowView struct {
+	Label                     string
+	Samples                   int
+	MAE, Bias, Brier          string
+	HitRate, FalseAlarm       string
+	Hits, Misses, FalseAlarms int
+}
+
+func newLeadRow(s models.LeadTimeStats) leadRowView {
+	var hitRate, falseAlarm *float64
+	if s.HitRate != nil {
+		v := *s.HitRate * 100
+		hitRate = &v
+	}
+	if s.FalseAlarmRatio != nil {
+		v := *s.FalseAlarmRatio * 100
+		falseAlarm = &v
+	}
+	return leadRowView{
+		Label:       s.Label,
+		Samples:     s.TmpSamples,
+		MAE:         formatStat(s.TmpMAE, "%.1f℃"),
+		Bias:        formatStat(s.TmpBias, "%+.1f℃"),
+		Brier:       formatStat(s.Brier, "%.3f"),
+		HitRate:     formatStat(hitRate, "%.0f%%"),
+		FalseAlarm:  formatStat(falseAlarm, "%.0f%%"),
+		Hits:        s.Hits,
+		Misses:      s.Misses,
+		FalseAlarms: s.FalseAlarms,
+	}
+}
+
+// GetAccuracyStats는 통계 페이지(stats.html)에 넣을 정확도 표를 반환합니다.
+func GetAccuracyStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	report, ok := accuracyFromRequest(w, r)
+	if !ok {
+		return
+	}
+	type calibrationView struct {
+		Range                  string
+		Samples                int
+		MeanForecast, Observed string
+	}
+	view := struct {
+		Empty          bool
+		From, To, Grid string
+		Leads          []leadRowView
+		Overall        leadRowView
+		Calibration    []calibrationView
+	}{
+		Empty:   report.Overall.TmpSamples == 0 && report.Overall.PopSamples == 0,
+		From:    report.From.Format("01/02"),
+		To:      report.To.Format("01/02"),
+		Grid:    report.Grid,
+		Overall: newLeadRow(report.Overall),
+	}
+	for _, lead := range report.Leads {
+		view.Leads = append(view.Leads, newLeadRow(lead))
+	}
+	for _, bin := range report.Calibration {
+		if bin.Samples == 0 {
+			continue
+		}
+		view.Calibration = append(view.Calibration, calibrationView{
+			Range:        fmt.Sprintf("%d~%d%%", bin.From, bin.To),
+			Samples:      bin.Samples,
+			MeanForecast: formatStat(bin.MeanForecast, "%.0f%%"),
+			Observed:     formatStat(bin.ObservedFrequency, "%.0f%%"),
+		})
+	}
+	renderFragment(w, "accuracy-stats", view)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 특정 날짜의 예보만 시간순으로 골라냅니다.
+func forecastForDate(allWeather []models.WeatherItem, date string) []models.WeatherItem {
+	var result []models.WeatherItem
+	for _, item := range allWeather {
+		if item.Date == date {
+			result = append(result, item)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time < result[j].Time
+	})
+	return result
+}
+
+// 브리핑을 로그에 남기고 구독 중인 브라우저로 보냅니다.
+func deliverBriefing(title, body string) {
+	log.Printf("브리핑 [%s] %s", title, body)
+	notify(models.Notification{
+		Title:    title,
+		Body:     body,
+		Tag:      "briefing",
+		URL:      "/",
+		Severity: models.SeverityInfo,
+	})
+}
+
+func runMorningBriefing() error {
+	allWeather, err := fetchAndCacheWeather()
+	if err != nil {
+		return err
+	}
+	today := time.Now().In(seoul).Format("20060102")
+	summary := summarizeDay(forecastForDate(allWeather, today), today, "오늘")
+	deliverBriefing("오늘의 날씨", summary.Text)
+	return nil
+}
+
+func runEveningSummary() error {
+	allWeather, err := fetchAndCacheWeather()
+	if err != nil {
+		return err
+	}
+	tomorrow := time.Now().In(seoul).AddDate(0, 0, 1).Format("20060102")
+	summary := summarizeDay(forecastForDate(allWeather, tomorrow), tomorrow, "내일")
+	deliverBriefing("내일의 날씨", summary.Text)
+	return nil
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 일정 날씨 패널과 알림이 보는 기간
+const calendarHorizon = 3 * 24 * time.Hour
+
+// 이 단어가 제목이나 장소에 들어 있으면 야외 일정으로 봅니다.
+// CALENDAR_OUTDOOR_KEYWORDS(쉼표 구분)로 단어를 더할 수 있습니다.
+var defaultOutdoorKeywords = []string{
+	"축구", "야구", "농구", "테니스", "배드민턴", "골프", "등산", "산행", "산책", "캠핑", "캠프",
+	"소풍", "피크닉", "자전거", "라이딩", "러닝", "마라톤", "조깅", "낚시", "운동회", "체육대회",
+	"야외", "공원", "바베큐", "텃밭", "나들이", "현장학습",
+	"soccer", "football", "baseball", "hiking", "picnic", "camping", "bbq", "outdoor", "park",
+}
+
+type calendarCache struct {
+	body      []byte
+	source    string
+	expiresAt time.Time
+	mutex     sync.Mutex
+}
+
+var icsCache = &calendarCache{}
+
+// CALENDAR_ICS에 지정한 파일 경로나 http(s)/webcal URL에서 ICS를 읽습니다. 30분 동안 캐시합니다.
+func loadCalendarSource() ([]byte, error) {
+	source := os.Getenv("CALENDAR_ICS")
+	if source == "" {
+		return nil, nil
+	}
+
+	icsCache.mutex.Lock()
+	defer icsCache.mutex.Unlock()
+	if icsCache.source == source && time.Now().Before(icsCache.expiresAt) {
+		return icsCache.body, nil
+	}
+
+	var body []byte
+	var err error
+	if strings.HasPrefix(source, "webcal://") {
+		// 구글 캘린더 등이 주는 webcal:// 주소는 실제로는 https입니다.
+		body, err = fetchICS("https://" + strings.TrimPrefix(source, "webcal://"))
+	} else if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		body, err = fetchICS(source)
+	} else {
+		body, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("캘린더 읽기 실패: %v", err)
+	}
+	icsCache.body, icsCache.source = body, source
+	icsCache.expiresAt = time.Now().Add(30 * time.Minute)
+	return body, nil
+}
+
+func fetchICS(url string) ([]byte, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("응답 코드 %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+}
+
+// upcomingEvents는 지금부터 horizon 안의 일정을 반환합니다. 캘린더가 설정되지 않았으면 nil입니다.
+func upcomingEvents(now time.Time, horizon time.Duration) ([]models.CalendarEvent, error) {
+	body, err := loadCalendarSource()
+	if err != nil || body == nil {
+		return nil, err
+	}
+	root, err := parseICS(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	return expandICSEvents(root, now, now.Add(horizon)), nil
+}
+
+// 이름으로 찾을 수 있는 장소들: 집, 프로필의 출발지/도착지, data/locations.json
+func knownLocations() []models.Location {
+	locations := []models.Location{homeLocation()}
+	for _, p := range profiles.list() {
+		if p.Origin.Name != "" && p.Origin.Nx != 0 {
+			locations = append(locations, p.Origin)
+		}
+		if p.Destination.Name != "" && p.Destination.Nx != 0 {
+			locations = append(locations, p.Destination)
+		}
+	}
+	var extra []models.Location
+	if err := readJSONFile(dataPath("locations.json"), &extra); err != nil {
+		log.Printf("장소 목록 불러오기 실패: %v", err)
+	}
+	return append(locations, extra...)
+}
+
+// resolveEventLocation은 일정 장소를 격자로 바꿉니다.
+// GEO 속성이 있으면 좌표로, 없으면 알려진 장소 이름이 LOCATION에 들어 있는지로 찾습니다.
+func resolveEventLocation(ev models.CalendarEvent) (models.Location, bool) {
+	if ev.Lat != nil && ev.Lon != nil {
+		nx, ny := latLonToGrid(*ev.Lat, *ev.Lon)
+		name := ev.Location
+		if name == "" {
+			name = "일정 장소"
+		}
+		return models.Location{Name: name, Nx: nx, Ny: ny}, true
+	}
+	if ev.Location != "" {
+		locations := knownLocations()
+		// "서울숲"과 "서울"이 모두 있으면 더 긴 이름을 먼저 맞춥니다.
+		sort.SliceStable(locations, func(i, j int) bool {
+			return len(locations[i].Name) > len(locations[j].Name)
+		})
+		for _, loc := range locations {
+			if loc.Name != "" && strings.Contains(strings.ToLower(ev.Location), strings.ToLower(loc.Name)) {
+				return loc, true
+			}
+		}
+	}
+	return homeLocation(), false
+}
+
+func outdoorKeywords() []string {
+	keywords := defaultOutdoorKeywords
+	for _, k := range strings.Split(os.Getenv("CALENDAR_OUTDOOR_KEYWORDS"), ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keywords = append(keywords, k)
+		}
+	}
+	return keywords
+}
+
+func isOutdoorEvent(ev models.CalendarEvent) bool {
+	text := strings.ToLower(ev.Summary + " " + ev.Location)
+	for _, keyword := range outdoorKeywords() {
+		if strings.Contains(text, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isBadWeather(item models.WeatherItem) bool {
+	pop, _ := numericValue(item.Pop)
+	if item.Pty != "none" || pop >= 60 {
+		return true
+	}
+	tmp, ok := numericValue(item.Tmp)
+	return ok && (tmp <= -5 || tmp >= 33)
+}
+
+// 일정 시간 동안 가장 나쁜 예보 칸을 고릅니다. 종일 일정은 9시~18시를 봅니다.
+func worstSlotDuring(items []models.WeatherItem, ev models.CalendarEvent) *models.WeatherItem {
+	start, end := ev.Start, ev.End
+	if ev.AllDay {
+		start = time.Date(start.Year(), start.Month(), start.Day(), 9, 0, 0, 0, seoul)
+		end = start.Add(9 * time.Hour)
+	}
+	if !end.After(start) {
+		end = start.Add(time.Hour)
+	}
+	var worst *models.WeatherItem
+	worstScore := -1.0
+	for _, slot := range slotsBetween(items, start, end) {
+		pop, _ := numericValue(slot.Pop)
+		score := pop + float64(precipSeverity(slot.Pty))*100
+		if score > worstScore {
+			s := slot
+			worst, worstScore = &s, score
+		}
+	}
+	return worst
+}
+
+// matchEventsWithWeather는 일정마다 장소/시각에 맞는 예보를 붙입니다.
+func matchEventsWithWeather(events []models.CalendarEvent) []models.EventWeather {
+	var result []models.EventWeather
+	for _, ev := range events {
+		loc, _ := resolveEventLocation(ev)
+		match := models.EventWeather{Event: ev, Location: loc, Outdoor: isOutdoorEvent(ev)}
+		if items, err := fetchAndCacheWeatherAt(loc); err != nil {
+			log.Printf("%s 예보를 가져오지 못했습니다: %v", loc.Name, err)
+		} else {
+			match.Weather = worstSlotDuring(items, ev)
+		}
+		match.Bad = match.Weather != nil && isBadWeather(*match.Weather)
+		result = append(result, match)
+	}
+	return result
+}
+
+var koreanWeekdays = []string{"일요일", "월요일", "화요일", "수요일", "목요일", "금요일", "토요일"}
+
+// "토요일 14:00 축구 — 🌧 70%"
+func eventWeatherLine(m models.EventWeather) string {
+	when := m.Event.Start.In(seoul)
+	clock := when.Format("15:04")
+	if m.Event.AllDay {
+		clock = "종일"
+	}
+	line := fmt.Sprintf("%s %s %s", koreanWeekdays[when.Weekday()], clock, m.Event.Summary)
+	if m.Weather == nil {
+		return line + " — 예보 없음"
+	}
+	icon := m.Weather.Sky
+	if m.Weather.Pty != "none" {
+		icon = m.Weather.Pty
+	}
+	return fmt.Sprintf("%s — %s %s", line, icon, m.Weather.Pop)
+}
+
+// calendarReminders는 앞으로 이틀 안의 야외 일정 중 날씨가 나쁜 일정에 대해 알림을 만듭니다.
+func calendarReminders(now time.Time) []models.Reminder {
+	events, err := upcomingEvents(now, 48*time.Hour)
+	if err != nil {
+		log.Printf("캘린더 불러오기 실패: %v", err)
+		return nil
+	}
+	var result []models.Reminder
+	for _, m := range matchEventsWithWeather(events) {
+		if !m.Outdoor || !m.Bad {
+			continue
+		}
+		result = append(result, models.Reminder{
+			ID:        fmt.Sprintf("event-%s-%s", m.Event.UID, m.Event.Start.Format("200601021504")),
+			Kind:      "event",
+			Severity:  models.SeverityWarning,
+			Title:     "야외 일정 날씨 주의",
+			Message:   eventWeatherLine(m),
+			Start:     m.Event.Start,
+			End:       m.Event.End,
+			CreatedAt: now,
+		})
+	}
+	return result
+}
+
+// GetCalendarWeather는 다가오는 일정과 그 시각의 예보를 "일정 날씨" 패널로 반환합니다.
+func GetCalendarWeather(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	events, err := upcomingEvents(time.Now().In(seoul), calendarHorizon)
+	if err != nil {
+		log.Printf("캘린더 불러오기 실패: %v", err)
+		http.Error(w, "일정을 불러올 수 없습니다.", http.StatusInternalServerError)
+		return
+	}
+	if len(events) == 0 {
+		// 캘린더가 없으면 패널을 비워 둡니다.
+		return
+	}
+
+	type eventView struct {
+		Line string
+		Bad  bool
+	}
+	var views []eventView
+	for _, m := range matchEventsWithWeather(events) {
+		views = append(views, eventView{Line: eventWeatherLine(m), Bad: m.Outdoor && m.Bad})
+	}
+	renderFragment(w, "calendar-weather", views)
+}
@@ -0,0 +1,510 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 피드에 넣는 날씨 일정 하나. UID는 예보가 바뀌어도 같은 일정을 가리키도록 종류와 날짜로 만듭니다.
+type feedEvent struct {
+	UID         string    `json:"uid"`
+	Summary     string    `json:"summary"`
+	Description string    `json:"description"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	AllDay      bool      `json:"allDay"`
+}
+
+// 내용이 같으면 해시도 같습니다. 해시가 바뀌면 SEQUENCE를 올립니다.
+func (e feedEvent) hash() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		e.Summary, e.Description, e.Start.UTC().Format(time.RFC3339), e.End.UTC().Format(time.RFC3339), fmt.Sprint(e.AllDay),
+	}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// feedEntryState는 UID별로 마지막으로 내보낸 내용과 SEQUENCE를 기억합니다.
+type feedEntryState struct {
+	Hash     string    `json:"hash"`
+	Sequence int       `json:"sequence"`
+	Stamp    time.Time `json:"stamp"`  // 마지막으로 내용이 바뀐 시각 (DTSTAMP/LAST-MODIFIED)
+	SeenAt   time.Time `json:"seenAt"` // 마지막으로 피드에 포함된 시각
+}
+
+type feedStateStore struct {
+	entries map[string]feedEntryState
+	mutex   sync.Mutex
+}
+
+var calendarFeedState = &feedStateStore{}
+
+func calendarFeedStatePath() string { return dataPath("calendar_feed.json") }
+
+// track은 이번에 내보낼 일정들의 SEQUENCE/DTSTAMP를 정합니다.
+// 사라졌던 일정이 다시 나타나도 SEQUENCE가 되돌아가지 않도록 2주 동안 기록을 남겨 둡니다.
+func (s *feedStateStore) track(events []feedEvent, now time.Time) map[string]feedEntryState {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.entries == nil {
+		s.entries = make(map[string]feedEntryState)
+		if err := readJSONFile(calendarFeedStatePath(), &s.entries); err != nil {
+			log.Printf("캘린더 피드 기록 불러오기 실패: %v", err)
+		}
+	}
+
+	changed := false
+	result := make(map[string]feedEntryState)
+	for _, ev := range events {
+		h := ev.hash()
+		entry, ok := s.entries[ev.UID]
+		switch {
+		case !ok:
+			entry = feedEntryState{Hash: h, Stamp: now}
+			changed = true
+		case entry.Hash != h:
+			entry.Hash = h
+			entry.Sequence++
+			entry.Stamp = now
+			changed = true
+		}
+		entry.SeenAt = now
+		s.entries[ev.UID] = entry
+		result[ev.UID] = entry
+	}
+	for uid, entry := range s.entries {
+		if now.Sub(entry.SeenAt) > 14*24*time.Hour {
+			delete(s.entries, uid)
+			changed = true
+		}
+	}
+
+	// SeenAt만 바뀐 경우는 매 요청마다 파일을 쓰지 않습니다.
+	if changed {
+		if err := writeJSONFile(calendarFeedStatePath(), s.entries); err != nil {
+			log.Printf("캘린더 피드 기록 저장 실패: %v", err)
+		}
+	}
+	return result
+}
+
+// rainPeriod는 비/눈이 이어지는 시간대 하나입니다.
+type rainPeriod struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Kind   string    `json:"kind"`
+	MaxPop float64   `json:"maxPop"`
+}
+
+func (p rainPeriod) same(o rainPeriod) bool {
+	return p.Start.Equal(o.Start) && p.End.Equal(o.End) && p.Kind == o.Kind && p.MaxPop == o.MaxPop
+}
+
+// 이미 시작된 비 시간대. 예보 구간(지금-1시간부터)에서 빠져도 그날 피드에 남고 번호가 바뀌지 않도록 기억합니다.
+var startedRain = struct {
+	periods []rainPeriod
+	loaded  bool
+	mutex   sync.Mutex
+}{}
+
+func startedRainPath() string { return dataPath("calendar_rain.json") }
+
+// rainPeriods는 시간순 예보에서 비/눈이 이어지는 시간대를 찾습니다.
+func rainPeriods(items []models.WeatherItem) []rainPeriod {
+	var periods []rainPeriod
+	var current *rainPeriod
+	for _, item := range items {
+		t, err := slotTime(item)
+		if err != nil {
+			continue
+		}
+		if item.Pty == "none" {
+			current = nil
+			continue
+		}
+		pop, _ := numericValue(item.Pop)
+		if current != nil && t.Equal(current.End) {
+			current.End = t.Add(time.Hour)
+			if pop > current.MaxPop {
+				current.MaxPop = pop
+			}
+			if precipSeverity(item.Pty) > precipSeverity(current.Kind) {
+				current.Kind = item.Pty
+			}
+			continue
+		}
+		periods = append(periods, rainPeriod{Start: t, End: t.Add(time.Hour), Kind: item.Pty, MaxPop: pop})
+		current = &periods[len(periods)-1]
+	}
+	return periods
+}
+
+// 같은 날 여러 번 오는 비는 그날 0시부터 센 몇 번째 비인지로 구분합니다.
+// 이미 시작된 비는 기억해 두었다가 예보 구간에서 빠져도 그대로 내보내고,
+// 아직 이어지는 비는 예보 구간이 밀려도 처음 시작 시각을 유지합니다.
+func rainPeriodEvents(items []models.WeatherItem, now time.Time) []feedEvent {
+	forecast := rainPeriods(items)
+	var windowStart time.Time
+	if len(items) > 0 {
+		windowStart, _ = slotTime(items[0])
+	}
+
+	startedRain.mutex.Lock()
+	defer startedRain.mutex.Unlock()
+	if !startedRain.loaded {
+		if err := readJSONFile(startedRainPath(), &startedRain.periods); err != nil {
+			log.Printf("비 시간대 기록 불러오기 실패: %v", err)
+		}
+		startedRain.loaded = true
+	}
+
+	var periods []rainPeriod
+	for _, past := range startedRain.periods {
+		// 예보 구간 안에서 이어지는 비는 새 예보와 합칩니다.
+		merged := false
+		for i := range forecast {
+			p := &forecast[i]
+			if p.Start.After(past.End) || p.End.Before(past.Start) {
+				continue
+			}
+			if past.Start.Before(p.Start) {
+				p.Start = past.Start
+			}
+			if past.MaxPop > p.MaxPop {
+				p.MaxPop = past.MaxPop
+			}
+			if precipSeverity(past.Kind) > precipSeverity(p.Kind) {
+				p.Kind = past.Kind
+			}
+			merged = true
+			break
+		}
+		if merged {
+			continue
+		}
+		// 지금 칸부터 비 예보가 없으면 그 전에 그친 것으로 봅니다.
+		if !windowStart.IsZero() && past.End.After(windowStart) {
+			past.End = windowStart
+		}
+		if past.End.After(past.Start) {
+			periods = append(periods, past)
+		}
+	}
+	periods = append(periods, forecast...)
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Start.Before(periods[j].Start) })
+
+	// 시작된 비만 기억하고, 이틀 지난 기록은 지웁니다.
+	var started []rainPeriod
+	for _, p := range periods {
+		if !p.Start.After(now) && now.Sub(p.End) < 48*time.Hour {
+			started = append(started, p)
+		}
+	}
+	changed := len(started) != len(startedRain.periods)
+	for i := 0; !changed && i < len(started); i++ {
+		changed = !started[i].same(startedRain.periods[i])
+	}
+	if changed {
+		startedRain.periods = started
+		if err := writeJSONFile(startedRainPath(), started); err != nil {
+			log.Printf("비 시간대 기록 저장 실패: %v", err)
+		}
+	}
+
+	var events []feedEvent
+	perDay := make(map[string]int)
+	for _, p := range periods {
+		day := p.Start.In(seoul).Format("20060102")
+		perDay[day]++
+		events = append(events, feedEvent{
+			UID:     fmt.Sprintf("rain-%s-%d", day, perDay[day]),
+			Summary: fmt.Sprintf("%s 예보", precipWord(p.Kind)),
+			Description: fmt.Sprintf("%s~%s %s, 강수확률 최대 %.0f%%",
+				p.Start.Format("15:04"), p.End.Format("15:04"), precipWord(p.Kind), p.MaxPop),
+			Start: p.Start,
+			End:   p.End,
+		})
+	}
+	return events
+}
+
+// extremeEvent는 서리나 폭염처럼 하루 중 가장 춥거나 더운 시각으로 정해지는 일정입니다.
+type extremeEvent struct {
+	Event feedEvent `json:"event"`
+	Value float64   `json:"value"` // 최저 기온(서리) 또는 최고 기온(폭염)
+	At    time.Time `json:"at"`    // 그 기온이 예보된 시각
+	Cold  bool      `json:"cold"`  // true면 낮을수록 심합니다
+}
+
+// worse는 e가 o보다 더 춥거나(서리) 더 더운지(폭염) 봅니다.
+func (e extremeEvent) worse(o extremeEvent) bool {
+	if e.Cold {
+		return e.Value < o.Value
+	}
+	return e.Value > o.Value
+}
+
+func (e extremeEvent) same(o extremeEvent) bool {
+	return e.Event.hash() == o.Event.hash() && e.Value == o.Value && e.At.Equal(o.At) && e.Cold == o.Cold
+}
+
+// 이미 지나간 가장 추운/더운 시각의 일정. 예보 구간이 밀려 그 시각이 빠져도 일정이 바뀌거나 사라지지 않도록 기억합니다.
+var pastExtremes = struct {
+	events map[string]extremeEvent
+	mutex  sync.Mutex
+}{}
+
+func pastExtremesPath() string { return dataPath("calendar_extremes.json") }
+
+// 저녁 18시부터 다음 날 9시까지 최저 기온이 0℃ 이하면 서리/결빙 주의 일정을 만듭니다.
+func frostNightEvents(items []models.WeatherItem) []extremeEvent {
+	var events []extremeEvent
+	seen := make(map[string]bool)
+	for _, item := range items {
+		t, err := slotTime(item)
+		if err != nil {
+			continue
+		}
+		night := time.Date(t.Year(), t.Month(), t.Day(), 18, 0, 0, 0, seoul)
+		if t.Hour() < 9 {
+			night = night.AddDate(0, 0, -1)
+		}
+		key := night.Format("20060102")
+		if seen[key] {
+			continue
+		}
+		minItem, _ := tempExtremes(slotsBetween(items, night, night.Add(15*time.Hour)))
+		if minItem == nil {
+			continue
+		}
+		seen[key] = true
+		value, _ := numericValue(minItem.Tmp)
+		if value > 0 {
+			continue
+		}
+		low, _ := slotTime(*minItem)
+		events = append(events, extremeEvent{
+			Event: feedEvent{
+				UID:         "frost-" + key,
+				Summary:     "서리·결빙 주의",
+				Description: fmt.Sprintf("%s 최저 %s, 차량 유리와 길 결빙에 주의하세요", formatTime(minItem.Time), minItem.Tmp),
+				Start:       low,
+				End:         low.Add(time.Hour),
+			},
+			Value: value,
+			At:    low,
+			Cold:  true,
+		})
+	}
+	return events
+}
+
+// 하루 최고 기온이 33℃ 이상인 날은 종일 일정으로 만듭니다.
+func heatDayEvents(items []models.WeatherItem) []extremeEvent {
+	byDate := make(map[string][]models.WeatherItem)
+	var dates []string
+	for _, item := range items {
+		if _, ok := byDate[item.Date]; !ok {
+			dates = append(dates, item.Date)
+		}
+		byDate[item.Date] = append(byDate[item.Date], item)
+	}
+	sort.Strings(dates)
+
+	var events []extremeEvent
+	for _, date := range dates {
+		_, maxItem := tempExtremes(byDate[date])
+		if maxItem == nil {
+			continue
+		}
+		value, _ := numericValue(maxItem.Tmp)
+		if value < 33 {
+			continue
+		}
+		day, err := time.ParseInLocation("20060102", date, seoul)
+		if err != nil {
+			continue
+		}
+		high, _ := slotTime(*maxItem)
+		events = append(events, extremeEvent{
+			Event: feedEvent{
+				UID:         "heat-" + date,
+				Summary:     fmt.Sprintf("폭염 주의 (최고 %s)", maxItem.Tmp),
+				Description: fmt.Sprintf("%s 최고 %s, 야외 활동을 줄이고 물을 자주 드세요", formatTime(maxItem.Time), maxItem.Tmp),
+				Start:       day,
+				End:         day.AddDate(0, 0, 1),
+				AllDay:      true,
+			},
+			Value: value,
+			At:    high,
+		})
+	}
+	return events
+}
+
+// extremeEventsWithPast는 예보로 만든 서리/폭염 일정에 이미 지나간 일정을 합칩니다.
+// 가장 춥거나 더운 시각이 지난 일정은 남은 예보가 더 심할 때만 바꾸고, 남은 예보에 없으면 그대로 내보냅니다.
+// 아직 오지 않은 일정은 예보를 그대로 따릅니다.
+func extremeEventsWithPast(forecast []extremeEvent, now time.Time) []feedEvent {
+	pastExtremes.mutex.Lock()
+	defer pastExtremes.mutex.Unlock()
+	if pastExtremes.events == nil {
+		pastExtremes.events = make(map[string]extremeEvent)
+		if err := readJSONFile(pastExtremesPath(), &pastExtremes.events); err != nil {
+			log.Printf("서리/폭염 일정 기록 불러오기 실패: %v", err)
+		}
+	}
+
+	merged := make(map[string]extremeEvent)
+	for _, e := range forecast {
+		merged[e.Event.UID] = e
+	}
+	for uid, past := range pastExtremes.events {
+		if e, ok := merged[uid]; !ok || !e.worse(past) {
+			merged[uid] = past
+		}
+	}
+
+	// 지나간 일정만 기억하고, 끝난 지 이틀 지난 기록은 지웁니다.
+	changed := false
+	for uid, e := range merged {
+		if e.At.After(now) {
+			continue
+		}
+		if now.Sub(e.Event.End) >= 48*time.Hour {
+			delete(merged, uid)
+			if _, ok := pastExtremes.events[uid]; ok {
+				delete(pastExtremes.events, uid)
+				changed = true
+			}
+			continue
+		}
+		if past, ok := pastExtremes.events[uid]; !ok || !past.same(e) {
+			pastExtremes.events[uid] = e
+			changed = true
+		}
+	}
+	if changed {
+		if err := writeJSONFile(pastExtremesPath(), pastExtremes.events); err != nil {
+			log.Printf("서리/폭염 일정 기록 저장 실패: %v", err)
+		}
+	}
+
+	events := make([]feedEvent, 0, len(merged))
+	for _, e := range merged {
+		events = append(events, e.Event)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].UID < events[j].UID })
+	return events
+}
+
+// buildFeedEvents는 집 날씨 알림과 집 예보에서 눈에 띄는 날씨를 모아 피드 일정을 만듭니다.
+func buildFeedEvents() ([]feedEvent, error) {
+	allWeather, err := fetchAndCacheWeather()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().In(seoul)
+	items := forecastWindow(allWeather, now, 72*time.Hour)
+
+	var events []feedEvent
+	for _, r := range activeReminders.list() {
+		// 피드는 인증 없이 열려 있으므로 날씨 규칙 알림만 내보냅니다.
+		// 가족 일정 제목이 들어간 일정 알림과 구성원별 통근 알림은 빼 둡니다.
+		if r.ProfileID != "" || r.Kind == "event" {
+			continue
+		}
+		events = append(events, feedEvent{
+			UID:         "reminder-" + r.ID,
+			Summary:     r.Title,
+			Description: r.Message,
+			Start:       r.Start,
+			End:         r.End,
+		})
+	}
+	events = append(events, rainPeriodEvents(items, now)...)
+	events = append(events, extremeEventsWithPast(append(frostNightEvents(items), heatDayEvents(items)...), now)...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start.Before(events[j].Start)
+	})
+	return events, nil
+}
+
+func escapeICSText(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(s)
+}
+
+// writeICSLine은 RFC 5545 3.1에 따라 75옥텟마다 줄을 접습니다. UTF-8 문자 중간에서는 자르지 않습니다.
+func writeICSLine(w io.Writer, line string) {
+	const limit = 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		fmt.Fprintf(w, "%s\r\n", line[:cut])
+		line = " " + line[cut:]
+	}
+	fmt.Fprintf(w, "%s\r\n", line)
+}
+
+func writeCalendarFeed(w io.Writer, events []feedEvent, states map[string]feedEntryState) {
+	const utcFormat = "20060102T150405Z"
+	writeICSLine(w, "BEGIN:VCALENDAR")
+	writeICSLine(w, "VERSION:2.0")
+	writeICSLine(w, "PRODID:-//weather-reminder//날씨 알림//KO")
+	writeICSLine(w, "CALSCALE:GREGORIAN")
+	writeICSLine(w, "METHOD:PUBLISH")
+	writeICSLine(w, "X-WR-CALNAME:날씨 알림")
+	writeICSLine(w, "X-WR-TIMEZONE:Asia/Seoul")
+	// 구독하는 앱이 1시간마다 새로 받아 가도록 요청합니다.
+	writeICSLine(w, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	writeICSLine(w, "X-PUBLISHED-TTL:PT1H")
+	for _, ev := range events {
+		state := states[ev.UID]
+		writeICSLine(w, "BEGIN:VEVENT")
+		writeICSLine(w, "UID:"+ev.UID+"@weather-reminder")
+		writeICSLine(w, "DTSTAMP:"+state.Stamp.UTC().Format(utcFormat))
+		writeICSLine(w, "LAST-MODIFIED:"+state.Stamp.UTC().Format(utcFormat))
+		writeICSLine(w, fmt.Sprintf("SEQUENCE:%d", state.Sequence))
+		if ev.AllDay {
+			writeICSLine(w, "DTSTART;VALUE=DATE:"+ev.Start.Format("20060102"))
+			writeICSLine(w, "DTEND;VALUE=DATE:"+ev.End.Format("20060102"))
+		} else {
+			writeICSLine(w, "DTSTART:"+ev.Start.UTC().Format(utcFormat))
+			writeICSLine(w, "DTEND:"+ev.End.UTC().Format(utcFormat))
+		}
+		writeICSLine(w, "SUMMARY:"+escapeICSText(ev.Summary))
+		if ev.Description != "" {
+			writeICSLine(w, "DESCRIPTION:"+escapeICSText(ev.Description))
+		}
+		// 날씨 일정이 다른 일정의 빈 시간을 차지하지 않도록 합니다.
+		writeICSLine(w, "TRANSP:TRANSPARENT")
+		writeICSLine(w, "END:VEVENT")
+	}
+	writeICSLine(w, "END:VCALENDAR")
+}
+
+// GetCalendarFeed는 알림과 주요 예보를 휴대폰 캘린더에서 구독할 수 있는 ICS로 내보냅니다.
+func GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	events, err := buildFeedEvents()
+	if err != nil {
+		http.Error(w, "날씨 정보를 가져올 수 없습니다.", http.StatusInternalServerError)
+		return
+	}
+	states := calendarFeedState.track(events, time.Now())
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="weather.ics"`)
+	writeCalendarFeed(w, events, states)
+}
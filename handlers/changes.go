@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 이 이상 달라져야 변경으로 봅니다.
+const (
+	popChangeThreshold  = 30.0 // 강수확률 (%p)
+	tempChangeThreshold = 2.0  // 최저/최고 기온 (℃)
+)
+
+// 하루를 6시간씩 나눈 구간
+var dayPeriods = []string{"새벽", "오전", "오후", "저녁"}
+
+type changeStore struct {
+	items  []models.ForecastChange
+	loaded bool
+	mutex  sync.Mutex
+}
+
+var forecastChanges = &changeStore{}
+
+func forecastChangesPath() string { return dataPath("forecast_changes.json") }
+
+// 호출 시 s.mutex를 잡고 있어야 합니다.
+func (s *changeStore) ensureLoaded() {
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+	if err := readJSONFile(forecastChangesPath(), &s.items); err != nil {
+		log.Printf("예보 변경 기록 불러오기 실패: %v", err)
+	}
+}
+
+// add는 새 변경을 더하고, 감지한 지 하루가 지났거나 영향 칸이 모두 지난 변경은 지웁니다.
+func (s *changeStore) add(changes []models.ForecastChange, now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ensureLoaded()
+
+	current := now.In(seoul).Add(-time.Hour).Format("200601021504")
+	kept := s.items[:0]
+	for _, c := range append(s.items, changes...) {
+		// 예보는 map에서 모은 것이라 칸 순서가 섞여 있을 수 있으므로 정렬해 두고 마지막 칸으로 판단합니다.
+		sort.Strings(c.Slots)
+		if now.Sub(c.DetectedAt) > 24*time.Hour || len(c.Slots) == 0 || c.Slots[len(c.Slots)-1] < current {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	s.items = kept
+	if err := writeJSONFile(forecastChangesPath(), s.items); err != nil {
+		log.Printf("예보 변경 기록 저장 실패: %v", err)
+	}
+}
+
+func (s *changeStore) list(grid string) []models.ForecastChange {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ensureLoaded()
+	var result []models.ForecastChange
+	for _, c := range s.items {
+		if grid == "" || c.Grid == grid {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// 예보 칸(날짜+시각)별로 그 칸에 영향을 준 변경 메시지들
+func (s *changeStore) bySlot(grid string) map[string][]string {
+	result := make(map[string][]string)
+	for _, c := range s.list(grid) {
+		for _, slot := range c.Slots {
+			result[slot] = append(result[slot], c.Message)
+		}
+	}
+	return result
+}
+
+// "오늘", "내일", "모레" 또는 "10월 22일"
+func relativeDayLabel(date string, now time.Time) string {
+	day, err := time.ParseInLocation("20060102", date, seoul)
+	if err != nil {
+		return date
+	}
+	now = now.In(seoul)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, seoul)
+	switch int(math.Round(day.Sub(today).Hours() / 24)) {
+	case 0:
+		return "오늘"
+	case 1:
+		return "내일"
+	case 2:
+		return "모레"
+	default:
+		return fmt.Sprintf("%d월 %d일", day.Month(), day.Day())
+	}
+}
+
+type slotPair struct {
+	key      string
+	old, new models.WeatherItem
+}
+
+// detectForecastChanges는 이전 예보와 새 예보에 모두 있는 칸만 비교합니다.
+// 지난 칸이나 한쪽에만 있는 칸(예보 기간이 밀려서 생긴 차이)은 보지 않습니다.
+func detectForecastChanges(grid string, previous, latest []models.WeatherItem, now time.Time) []models.ForecastChange {
+	oldBySlot := make(map[string]models.WeatherItem, len(previous))
+	for _, item := range previous {
+		oldBySlot[item.Date+item.Time] = item
+	}
+	current := now.In(seoul).Format("20060102") + now.In(seoul).Format("15") + "00"
+
+	byPeriod := make(map[string][]slotPair) // "20261020|1" → 그 날 오전 칸들
+	byDate := make(map[string][]slotPair)
+	for _, item := range latest {
+		key := item.Date + item.Time
+		old, ok := oldBySlot[key]
+		if !ok || key < current || len(item.Time) < 2 {
+			continue
+		}
+		pair := slotPair{key: key, old: old, new: item}
+		hour := int(item.Time[0]-'0')*10 + int(item.Time[1]-'0')
+		periodKey := fmt.Sprintf("%s|%d", item.Date, hour/6)
+		byPeriod[periodKey] = append(byPeriod[periodKey], pair)
+		byDate[item.Date] = append(byDate[item.Date], pair)
+	}
+
+	stamp := now.In(seoul).Format("2006010215")
+	var changes []models.ForecastChange
+	periodKeys := make([]string, 0, len(byPeriod))
+	for key := range byPeriod {
+		periodKeys = append(periodKeys, key)
+	}
+	sort.Strings(periodKeys)
+	for _, key := range periodKeys {
+		pairs := byPeriod[key]
+		date, periodText, _ := strings.Cut(key, "|")
+		period, _ := strconv.Atoi(periodText)
+		label := relativeDayLabel(date, now) + " " + dayPeriods[period]
+
+		var oldPop, newPop float64
+		oldRain, newRain := "", ""
+		var affected []string
+		for _, p := range pairs {
+			op, _ := numericValue(p.old.Pop)
+			np, _ := numericValue(p.new.Pop)
+			oldPop, newPop = math.Max(oldPop, op), math.Max(newPop, np)
+			if p.old.Pty != "none" && precipSeverity(p.old.Pty) >= precipSeverity(oldRain) {
+				oldRain = p.old.Pty
+			}
+			if p.new.Pty != "none" && precipSeverity(p.new.Pty) >= precipSeverity(newRain) {
+				newRain = p.new.Pty
+			}
+			if math.Abs(np-op) >= popChangeThreshold/2 || (p.old.Pty == "none") != (p.new.Pty == "none") {
+				affected = append(affected, p.key)
+			}
+		}
+		popArrow := fmt.Sprintf("강수확률 %.0f%%→%.0f%%", oldPop, newPop)
+
+		change := models.ForecastChange{Grid: grid, Date: date, Slots: affected, DetectedAt: now}
+		switch {
+		case oldRain == "" && newRain != "":
+			change.Kind = "precip"
+			change.Message = fmt.Sprintf("%s %s 예보가 새로 생겼어요 (%s)", label, precipWord(newRain), popArrow)
+			change.Old, change.New = "none", newRain
+		case oldRain != "" && newRain == "":
+			change.Kind = "precip"
+			change.Message = fmt.Sprintf("%s %s 예보가 사라졌어요 (%s)", label, precipWord(oldRain), popArrow)
+			change.Old, change.New = oldRain, "none"
+		case math.Abs(newPop-oldPop) >= popChangeThreshold:
+			change.Kind = "pop"
+			change.Message = fmt.Sprintf("%s %s", label, popArrow)
+			change.Old, change.New = fmt.Sprintf("%.0f%%", oldPop), fmt.Sprintf("%.0f%%", newPop)
+		default:
+			continue
+		}
+		if len(change.Slots) == 0 {
+			for _, p := range pairs {
+				change.Slots = append(change.Slots, p.key)
+			}
+		}
+		change.ID = fmt.Sprintf("%s-%s-%s-%d-%s", grid, change.Kind, date, period, stamp)
+		changes = append(changes, change)
+	}
+
+	dates := make([]string, 0, len(byDate))
+	for date := range byDate {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	for _, date := range dates {
+		var olds, news []models.WeatherItem
+		for _, p := range byDate[date] {
+			olds = append(olds, p.old)
+			news = append(news, p.new)
+		}
+		oldMin, oldMax := tempExtremes(olds)
+		newMin, newMax := tempExtremes(news)
+		if oldMin == nil || newMin == nil {
+			continue
+		}
+		for _, extreme := range []struct {
+			kind, word string
+			old, new   *models.WeatherItem
+		}{
+			{"tmin", "최저기온", oldMin, newMin},
+			{"tmax", "최고기온", oldMax, newMax},
+		} {
+			o, _ := numericValue(extreme.old.Tmp)
+			n, _ := numericValue(extreme.new.Tmp)
+			diff := n - o
+			if math.Abs(diff) < tempChangeThreshold {
+				continue
+			}
+			direction := "상향"
+			if diff < 0 {
+				direction = "하향"
+			}
+			changes = append(changes, models.ForecastChange{
+				ID:         fmt.Sprintf("%s-%s-%s-%s", grid, extreme.kind, date, stamp),
+				Grid:       grid,
+				Kind:       extreme.kind,
+				Date:       date,
+				Message:    fmt.Sprintf("%s %s %.0f℃ %s (%s→%s)", relativeDayLabel(date, now), extreme.word, math.Abs(diff), direction, extreme.old.Tmp, extreme.new.Tmp),
+				Old:        extreme.old.Tmp,
+				New:        extreme.new.Tmp,
+				Slots:      []string{extreme.new.Date + extreme.new.Time},
+				DetectedAt: now,
+			})
+		}
+	}
+	return changes
+}
+
+// recordForecastChanges는 새로 받은 예보를 직전 예보와 비교해 변경을 저장합니다.
+// FORECAST_CHANGE_NOTIFY=true면 집의 강수 관련 변경을 알림으로도 보냅니다.
+func recordForecastChanges(loc models.Location, previous, latest []models.WeatherItem) {
+	now := time.Now().In(seoul)
+	changes := detectForecastChanges(locationKey(loc), previous, latest, now)
+	if len(changes) == 0 {
+		return
+	}
+	for _, c := range changes {
+		log.Printf("예보 변경 (%s): %s", loc.Name, c.Message)
+	}
+	forecastChanges.add(changes, now)
+
+	if os.Getenv("FORECAST_CHANGE_NOTIFY") != "true" || locationKey(loc) != locationKey(homeLocation()) {
+		return
+	}
+	var messages []string
+	for _, c := range changes {
+		if c.Kind == "precip" || c.Kind == "pop" {
+			messages = append(messages, c.Message)
+		}
+	}
+	if len(messages) > 0 {
+		notify(models.Notification{
+			Title:    "예보 변경",
+			Body:     strings.Join(messages, "\n"),
+			Tag:      "forecast-change",
+			URL:      "/",
+			Severity: models.SeverityInfo,
+		})
+	}
+}
+
+// 예보 칸의 "예보 변경" 배지에 title로 보여줄 변경 내용. 바뀐 게 없으면 빈 문자열입니다.
+func changeNote(slots map[string][]string, item models.WeatherItem) string {
+	return strings.Join(slots[item.Date+item.Time], "\n")
+}
+
+// GetForecastChanges는 최근 감지된 예보 변경을 JSON으로 반환합니다. (?grid=nx,ny)
+func GetForecastChanges(w http.ResponseWriter, r *http.Request) {
+	grid, err := gridFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	changes := forecastChanges.list(grid)
+	if changes == nil {
+		changes = []models.ForecastChange{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(changes)
+}
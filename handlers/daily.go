@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// dailyAggregate는 하루치 예보를 최저/최고기온, 강수량 합계, 최대 강수확률, 대표 하늘 상태로 모읍니다.
+// 최저/최고기온은 기상청 TMN/TMX가 있으면 그 값을, 없으면 시간별 기온 중 최저/최고를 씁니다.
+func dailyAggregate(date string, items []models.WeatherItem) models.DailyAggregate {
+	agg := models.DailyAggregate{Date: date}
+	skyCount := make(map[string]int)
+	for _, item := range items {
+		if item.Date != date {
+			continue
+		}
+		agg.Hours++
+		if v, ok := numericValue(item.Tmn); ok {
+			agg.MinTemp, agg.MinFromTMN = &v, true
+		}
+		if v, ok := numericValue(item.Tmx); ok {
+			agg.MaxTemp, agg.MaxFromTMX = &v, true
+		}
+		if v, ok := parsePrecipAmount(item.Pcp); ok {
+			agg.Precip += v
+		}
+		if v, ok := parsePrecipAmount(item.Sno); ok {
+			agg.Snow += v
+		}
+		if pop, ok := numericValue(item.Pop); ok && pop > agg.MaxPop {
+			agg.MaxPop = pop
+		}
+		if item.Pty != "none" && item.Pty != "" {
+			agg.PrecipHours++
+			if precipSeverity(item.Pty) > precipSeverity(agg.PrecipKind) || agg.PrecipKind == "" {
+				agg.PrecipKind = item.Pty
+			}
+		}
+		if item.Sky != "" {
+			skyCount[item.Sky]++
+		}
+	}
+
+	minItem, maxItem := tempExtremes(forecastForDate(items, date))
+	if minItem != nil {
+		// TMN/TMX가 없거나, 시간별 기온이 그 범위를 벗어나면 시간별 값으로 보정합니다.
+		if low, _ := numericValue(minItem.Tmp); agg.MinTemp == nil || low < *agg.MinTemp {
+			agg.MinTemp, agg.MinFromTMN = &low, false
+		}
+		if high, _ := numericValue(maxItem.Tmp); agg.MaxTemp == nil || high > *agg.MaxTemp {
+			agg.MaxTemp, agg.MaxFromTMX = &high, false
+		}
+	}
+
+	// 가장 많이 나온 하늘 상태. 같으면 더 흐린 쪽을 고릅니다.
+	for sky, count := range skyCount {
+		best := skyCount[agg.Sky]
+		if count > best || (count == best && skyRank(skyWord(sky)) > skyRank(skyWord(agg.Sky))) {
+			agg.Sky = sky
+		}
+	}
+	return agg
+}
+
+// dailyAggregates는 예보에 있는 날짜마다 집계를 만들어 날짜순으로 반환합니다.
+func dailyAggregates(allWeather []models.WeatherItem) []models.DailyAggregate {
+	seen := make(map[string]bool)
+	var dates []string
+	for _, item := range allWeather {
+		if !seen[item.Date] {
+			seen[item.Date] = true
+			dates = append(dates, item.Date)
+		}
+	}
+	sort.Strings(dates)
+	result := make([]models.DailyAggregate, 0, len(dates))
+	for _, date := range dates {
+		result = append(result, dailyAggregate(date, allWeather))
+	}
+	return result
+}
+
+// 합계가 1 미만이면 기상청 표기처럼 "1mm 미만"으로 보여줍니다.
+func amountText(v float64, unit string) string {
+	if v < 1 {
+		return "1" + unit + " 미만"
+	}
+	return fmt.Sprintf("%.1f%s", v, unit)
+}
+
+// dailySummary는 날짜 묶음 위에 붙이는 한 줄 요약입니다.
+// "☁ 3℃ / 12℃ · 🌧 5.0mm (6시간) · 강수확률 최대 80%"
+type dailySummary struct {
+	Sky     string
+	SkyWord string
+	MinTemp string // 최저/최고기온을 모르면 빈 문자열
+	MaxTemp string
+	Precip  string // 강수가 없으면 빈 문자열
+	MaxPop  string
+}
+
+func dailyHeader(agg models.DailyAggregate) *dailySummary {
+	if agg.Hours == 0 {
+		return nil
+	}
+	summary := &dailySummary{Sky: agg.Sky, SkyWord: skyWord(agg.Sky), MaxPop: fmt.Sprintf("%.0f%%", agg.MaxPop)}
+	if agg.MinTemp != nil && agg.MaxTemp != nil {
+		summary.MinTemp, summary.MaxTemp = fmt.Sprintf("%.0f℃", *agg.MinTemp), fmt.Sprintf("%.0f℃", *agg.MaxTemp)
+	}
+	if agg.PrecipHours > 0 {
+		var amounts []string
+		if agg.Precip > 0 {
+			amounts = append(amounts, amountText(agg.Precip, "mm"))
+		}
+		if agg.Snow > 0 {
+			amounts = append(amounts, "적설 "+amountText(agg.Snow, "cm"))
+		}
+		text := precipWord(agg.PrecipKind)
+		if len(amounts) > 0 {
+			text = strings.Join(amounts, ", ")
+		}
+		summary.Precip = fmt.Sprintf("%s %s (%d시간)", agg.PrecipKind, text, agg.PrecipHours)
+	}
+	return summary
+}
+
+// GetDailyAggregates는 날짜별 집계를 JSON으로 반환합니다.
+func GetDailyAggregates(w http.ResponseWriter, r *http.Request) {
+	allWeather, err := fetchAndCacheWeather()
+	if err != nil {
+		http.Error(w, "날씨 정보를 가져올 수 없습니다.", http.StatusInternalServerError)
+		return
+	}
+	today := time.Now().In(seoul).Format("20060102")
+	var result []models.DailyAggregate
+	for _, agg := range dailyAggregates(allWeather) {
+		if agg.Date >= today {
+			result = append(result, agg)
+		}
+	}
+	if result == nil {
+		result = []models.DailyAggregate{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
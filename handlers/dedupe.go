@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"hash/fnv"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 같은 사건을 여러 언론사가 조금씩 다른 제목으로 낸 기사를 한 묶음으로 봅니다.
+// 제목을 글자 2-gram(shingle) 집합으로 바꾸고, MinHash 서명으로 Jaccard 유사도를 추정해
+// 기준값 이상이면 같은 묶음에 넣습니다.
+
+const (
+	shingleSize            = 2    // 한글은 두 글자씩 끊어야 조사·어미가 달라도 겹치는 부분이 많습니다.
+	minHashSize            = 64   // 서명 길이. 길수록 추정이 정확하고 느립니다.
+	defaultDedupeThreshold = 0.35 // 같은 사건 제목끼리는 보통 0.4 안팎, 다른 기사는 0.2 아래입니다.
+)
+
+// newsSimilarityThreshold는 NEWS_SIMILARITY_THRESHOLD(0~1)입니다. 1이면 제목이 사실상 같을 때만 묶습니다.
+func newsSimilarityThreshold() float64 {
+	if v, err := strconv.ParseFloat(os.Getenv("NEWS_SIMILARITY_THRESHOLD"), 64); err == nil && v > 0 && v <= 1 {
+		return v
+	}
+	return defaultDedupeThreshold
+}
+
+// normalizeNewsTitle은 태그와 [속보], (종합) 같은 머릿말을 지우고 글자와 숫자만 소문자로 남깁니다.
+func normalizeNewsTitle(title string) string {
+	title = newsTitleCleaner.ReplaceAllString(cleanNewsText(title), "")
+	var b strings.Builder
+	for _, r := range title {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// titleShingles는 글자 n-gram 집합입니다. n보다 짧은 제목은 제목 전체가 하나의 shingle입니다.
+func titleShingles(s string, n int) []string {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return nil
+	}
+	if len(runes) <= n {
+		return []string{s}
+	}
+	seen := make(map[string]bool)
+	var result []string
+	for i := 0; i+n <= len(runes); i++ {
+		shingle := string(runes[i : i+n])
+		if !seen[shingle] {
+			seen[shingle] = true
+			result = append(result, shingle)
+		}
+	}
+	return result
+}
+
+// splitmix64로 shingle 해시를 minHashSize개의 서로 다른 해시 함수처럼 섞습니다.
+func mixHash(h, seed uint64) uint64 {
+	z := h + seed*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+type minHash [minHashSize]uint64
+
+func minHashSignature(shingles []string) minHash {
+	var sig minHash
+	for i := range sig {
+		sig[i] = ^uint64(0)
+	}
+	for _, shingle := range shingles {
+		hasher := fnv.New64a()
+		hasher.Write([]byte(shingle))
+		h := hasher.Sum64()
+		for i := range sig {
+			if v := mixHash(h, uint64(i+1)); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig
+}
+
+// similarity는 두 서명에서 추정한 Jaccard 유사도(0~1)입니다.
+func (a minHash) similarity(b minHash) float64 {
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / minHashSize
+}
+
+// newsCluster는 같은 사건으로 본 기사 묶음입니다. items는 들어온 순서(순위순)입니다.
+type newsCluster struct {
+	items []models.NewsItem
+	lead  minHash // 묶음의 첫 기사(순위가 가장 높은 기사) 제목의 서명
+}
+
+// clusterArticles는 기사를 순서대로 보며, 첫 기사와의 유사도가 threshold 이상인 묶음 중 가장 비슷한 묶음에 넣고
+// 없으면 새 묶음을 만듭니다. 묶음의 첫 기사와만 비교하므로, A와 B, B와 C가 비슷하다는 이유로
+// 서로 다른 A와 C가 사슬처럼 한 묶음이 되지 않습니다.
+// 묶음 순서는 각 묶음의 첫 기사 순서(검색 순위나 최신순)를 따릅니다.
+func clusterArticles(items []models.NewsItem, threshold float64) []*newsCluster {
+	var clusters []*newsCluster
+	for _, item := range items {
+		normalized := normalizeNewsTitle(item.Title)
+		if normalized == "" {
+			continue
+		}
+		sig := minHashSignature(titleShingles(normalized, shingleSize))
+
+		var best *newsCluster
+		bestScore := 0.0
+		for _, cluster := range clusters {
+			if score := sig.similarity(cluster.lead); score >= threshold && score > bestScore {
+				best, bestScore = cluster, score
+			}
+		}
+		if best == nil {
+			best = &newsCluster{lead: sig}
+			clusters = append(clusters, best)
+		}
+		best.items = append(best.items, item)
+	}
+	return clusters
+}
+
+// representative는 묶음을 대표할 기사입니다. 순위가 가장 높은 기사 중 열 수 있는 링크와 요약이 있는 것을 고르고,
+// 나머지 기사 제목은 Related에 담습니다.
+func (c *newsCluster) representative() models.NewsItem {
+	best := 0
+	score := func(item models.NewsItem) int {
+		s := 0
+		if safeLink(item.Link) != "" {
+			s += 2
+		}
+		if cleanNewsText(item.Description) != "" {
+			s++
+		}
+		return s
+	}
+	for i, item := range c.items {
+		if score(item) > score(c.items[best]) {
+			best = i
+		}
+	}
+	rep := c.items[best]
+	rep.Related = nil
+	for i, item := range c.items {
+		if i == best {
+			continue
+		}
+		title := cleanNewsText(item.Title)
+		if item.Source != "" {
+			title += " - " + item.Source
+		}
+		rep.Related = append(rep.Related, title)
+	}
+	return rep
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 같은 사건을 다룬 제목 (글자 2-gram Jaccard가 기준값보다 충분히 높은 쌍)
+var sameStoryTitles = [][2]string{
+	{"한국은행, 기준금리 3.25% 동결", "[속보] 한국은행 기준금리 3.25%로 동결"},
+	{"<b>한국은행</b> 기준금리 3.25% 동결 (종합)", "한국은행 기준금리 3.25% 동결"},
+	{"삼성전자 3분기 영업이익 9조1천억…예상치 밑돌아", "삼성전자 3분기 영업이익 9조1천억원, 시장 예상 하회"},
+	{"서울 첫눈 관측…평년보다 8일 늦어", "서울에 올겨울 첫눈…평년보다 8일 늦게 내려"},
+	{"내일 전국 비…돌풍·천둥번개 동반", "내일 전국에 비, 강한 돌풍과 천둥·번개 동반"},
+}
+
+// 낱말 몇 개가 겹치지만 다른 사건인 제목
+var nearMissTitles = [][2]string{
+	{"한국은행 기준금리 3.25% 동결", "미국 연준 기준금리 0.5%p 인하"},
+	{"삼성전자 3분기 영업이익 9조1천억", "삼성전자 노조 총파업 선언"},
+	{"삼성전자 3분기 영업이익 9조1천억", "SK하이닉스 3분기 영업이익 사상 최대"},
+	{"서울 첫눈 관측", "서울 지하철 파업 예고"},
+	{"내일 전국 비…돌풍·천둥번개 동반", "내일 전국 맑고 일교차 커"},
+	{"정부, 내년 최저임금 1만30원 확정", "정부, 내년 예산 677조 편성"},
+	{"서울 아파트값 상승폭 확대", "부산 아파트값 하락폭 커져"},
+}
+
+func newsItems(titles ...string) []models.NewsItem {
+	items := make([]models.NewsItem, len(titles))
+	for i, title := range titles {
+		items[i] = models.NewsItem{Title: title}
+	}
+	return items
+}
+
+func clusterTitles(clusters []*newsCluster) [][]string {
+	var result [][]string
+	for _, cluster := range clusters {
+		var titles []string
+		for _, item := range cluster.items {
+			titles = append(titles, item.Title)
+		}
+		result = append(result, titles)
+	}
+	return result
+}
+
+func titleSignature(title string) minHash {
+	return minHashSignature(titleShingles(normalizeNewsTitle(title), shingleSize))
+}
+
+// 두 제목의 실제 Jaccard 유사도
+func exactJaccard(a, b string) float64 {
+	set := make(map[string]bool)
+	for _, s := range titleShingles(normalizeNewsTitle(a), shingleSize) {
+		set[s] = true
+	}
+	shared, total := 0, len(set)
+	for _, s := range titleShingles(normalizeNewsTitle(b), shingleSize) {
+		if set[s] {
+			shared++
+		} else {
+			total++
+		}
+	}
+	return float64(shared) / float64(total)
+}
+
+func TestClusterArticlesSameStory(t *testing.T) {
+	for _, pair := range sameStoryTitles {
+		if clusters := clusterArticles(newsItems(pair[0], pair[1]), defaultDedupeThreshold); len(clusters) != 1 {
+			t.Errorf("같은 기사로 묶여야 합니다 (유사도 %.2f): %q / %q",
+				titleSignature(pair[0]).similarity(titleSignature(pair[1])), pair[0], pair[1])
+		}
+	}
+}
+
+func TestClusterArticlesNearMiss(t *testing.T) {
+	for _, pair := range nearMissTitles {
+		if clusters := clusterArticles(newsItems(pair[0], pair[1]), defaultDedupeThreshold); len(clusters) != 2 {
+			t.Errorf("다른 기사로 나뉘어야 합니다 (유사도 %.2f): %q / %q",
+				titleSignature(pair[0]).similarity(titleSignature(pair[1])), pair[0], pair[1])
+		}
+	}
+}
+
+func TestMinHashEstimatesJaccard(t *testing.T) {
+	for _, pair := range append(append([][2]string{}, sameStoryTitles...), nearMissTitles...) {
+		exact := exactJaccard(pair[0], pair[1])
+		estimate := titleSignature(pair[0]).similarity(titleSignature(pair[1]))
+		if math.Abs(exact-estimate) > 0.15 {
+			t.Errorf("%q / %q: 추정 %.2f, 실제 %.2f", pair[0], pair[1], estimate, exact)
+		}
+	}
+}
+
+// 틀이 같은 제목은 기본값에서 묶일 수 있으므로, 기준값을 올리면 나뉘어야 합니다.
+func TestClusterArticlesStricterThreshold(t *testing.T) {
+	items := newsItems(
+		"서울 아파트값 상승폭 확대",
+		"한국은행, 기준금리 3.25% 동결",
+		"경기 아파트값 상승폭 확대",
+		"[속보] 한국은행 기준금리 3.25%로 동결",
+	)
+	want := [][]string{
+		{"서울 아파트값 상승폭 확대"},
+		{"한국은행, 기준금리 3.25% 동결", "[속보] 한국은행 기준금리 3.25%로 동결"},
+		{"경기 아파트값 상승폭 확대"},
+	}
+	if got := clusterTitles(clusterArticles(items, 0.7)); !reflect.DeepEqual(got, want) {
+		t.Errorf("clusterArticles(0.7) = %q, want %q", got, want)
+	}
+}
+
+// B가 A와도, C와도 비슷하지만 C는 묶음의 첫 기사 A와 닮지 않았으므로 따로 묶입니다.
+func TestClusterArticlesNoChaining(t *testing.T) {
+	a, b, c := "서울 첫눈 관측", "서울 첫눈 관측…평년보다 8일 늦어", "평년보다 8일 늦게 개화"
+	const threshold = 0.3
+	if titleSignature(a).similarity(titleSignature(b)) < threshold || titleSignature(b).similarity(titleSignature(c)) < threshold {
+		t.Fatal("예시 제목의 유사도가 기준값보다 낮습니다")
+	}
+	want := [][]string{{a, b}, {c}}
+	if got := clusterTitles(clusterArticles(newsItems(a, b, c), threshold)); !reflect.DeepEqual(got, want) {
+		t.Errorf("clusterArticles = %q, want %q", got, want)
+	}
+}
+
+func TestClusterArticlesOrderAndEmptyTitles(t *testing.T) {
+	items := newsItems(
+		"서울 첫눈 관측…평년보다 8일 늦어",
+		"<b></b>",
+		"삼성전자 3분기 영업이익 9조1천억…예상치 밑돌아",
+		"서울에 올겨울 첫눈…평년보다 8일 늦게 내려",
+	)
+	want := [][]string{
+		{"서울 첫눈 관측…평년보다 8일 늦어", "서울에 올겨울 첫눈…평년보다 8일 늦게 내려"},
+		{"삼성전자 3분기 영업이익 9조1천억…예상치 밑돌아"},
+	}
+	if got := clusterTitles(clusterArticles(items, defaultDedupeThreshold)); !reflect.DeepEqual(got, want) {
+		t.Errorf("clusterArticles = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,338 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 수신자 설정이 없거나 recipientId 없이 구독한 브라우저가 속하는 수신자
+const defaultRecipientID = "default"
+
+type deliveryStore struct {
+	recipients []models.Recipient
+	queue      []models.QueuedNotification
+	lastDigest map[string]string // 수신자별 마지막 요약 발송 날짜 (20060102)
+	loaded     bool
+	mutex      sync.Mutex
+}
+
+// 보류 중인 알림과 요약 발송 기록을 함께 저장하는 파일 구조
+type deliveryQueueState struct {
+	Queue      []models.QueuedNotification `json:"queue"`
+	LastDigest map[string]string           `json:"lastDigest"`
+}
+
+var delivery = &deliveryStore{}
+
+func recipientsPath() string        { return dataPath("recipients.json") }
+func notificationQueuePath() string { return dataPath("notification_queue.json") }
+
+func defaultRecipients() []models.Recipient {
+	return []models.Recipient{{
+		ID:          defaultRecipientID,
+		Name:        "기본",
+		Channels:    []string{"push"},
+		MinSeverity: models.SeverityInfo,
+	}}
+}
+
+// 호출 시 s.mutex를 잡고 있어야 합니다.
+func (s *deliveryStore) ensureLoaded() {
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+	if err := readJSONFile(recipientsPath(), &s.recipients); err != nil {
+		log.Printf("수신자 설정 불러오기 실패: %v", err)
+	}
+	if len(s.recipients) == 0 {
+		s.recipients = defaultRecipients()
+	}
+	var state deliveryQueueState
+	if err := readJSONFile(notificationQueuePath(), &state); err != nil {
+		log.Printf("알림 대기열 불러오기 실패: %v", err)
+	}
+	s.queue = state.Queue
+	s.lastDigest = state.LastDigest
+	if s.lastDigest == nil {
+		s.lastDigest = make(map[string]string)
+	}
+}
+
+// 호출 시 s.mutex를 잡고 있어야 합니다.
+func (s *deliveryStore) saveQueue() {
+	state := deliveryQueueState{Queue: s.queue, LastDigest: s.lastDigest}
+	if err := writeJSONFile(notificationQueuePath(), state); err != nil {
+		log.Printf("알림 대기열 저장 실패: %v", err)
+	}
+}
+
+// "07:30"을 자정부터의 분으로 바꿉니다.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("시각 형식은 HH:MM 이어야 합니다: %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// 자정을 넘어가는 구간("23:00"~"07:00")도 처리합니다.
+func inClockRange(now time.Time, start, end string) bool {
+	if start == "" || end == "" {
+		return false
+	}
+	from, err1 := parseClock(start)
+	to, err2 := parseClock(end)
+	if err1 != nil || err2 != nil || from == to {
+		return false
+	}
+	minute := now.Hour()*60 + now.Minute()
+	if from < to {
+		return minute >= from && minute < to
+	}
+	return minute >= from || minute < to
+}
+
+func inQuietHours(r models.Recipient, now time.Time) bool {
+	return inClockRange(now, r.QuietStart, r.QuietEnd)
+}
+
+func deliverTo(r models.Recipient, n models.Notification) {
+	for _, channel := range r.Channels {
+		switch channel {
+		case "push":
+			sendPushTo(r.ID, n)
+		case "log":
+			log.Printf("알림 → %s: [%s] %s", r.Name, n.Title, n.Body)
+		}
+	}
+}
+
+// notify는 수신자별 설정(최소 심각도, 방해 금지 시간, 요약 모드)에 따라 알림을 보내거나 보류합니다.
+func notify(n models.Notification) {
+	notifyWhere(n, func(models.Recipient) bool { return true })
+}
+
+// notifyRecipient는 한 수신자에게만 알림을 보냅니다.
+func notifyRecipient(recipientID string, n models.Notification) {
+	if recipientID == "" {
+		recipientID = defaultRecipientID
+	}
+	notifyWhere(n, func(r models.Recipient) bool { return r.ID == recipientID })
+}
+
+func notifyWhere(n models.Notification, match func(models.Recipient) bool) {
+	now := time.Now().In(seoul)
+
+	delivery.mutex.Lock()
+	delivery.ensureLoaded()
+	var sendNow []models.Recipient
+	queued := false
+	for _, r := range delivery.recipients {
+		if !match(r) || severityRank(n.Severity) < severityRank(r.MinSeverity) {
+			continue
+		}
+		switch {
+		case r.Digest:
+			delivery.queue = append(delivery.queue, models.QueuedNotification{
+				RecipientID: r.ID, Notification: n, QueuedAt: now, Digest: true,
+			})
+			queued = true
+		case inQuietHours(r, now):
+			delivery.queue = append(delivery.queue, models.QueuedNotification{
+				RecipientID: r.ID, Notification: n, QueuedAt: now,
+			})
+			queued = true
+		default:
+			sendNow = append(sendNow, r)
+		}
+	}
+	if queued {
+		delivery.saveQueue()
+	}
+	delivery.mutex.Unlock()
+
+	for _, r := range sendNow {
+		deliverTo(r, n)
+	}
+}
+
+// 보류된 알림 여러 건을 하나로 합칩니다.
+func combineNotifications(title string, items []models.QueuedNotification) models.Notification {
+	if len(items) == 1 {
+		return items[0].Notification
+	}
+	lines := make([]string, 0, len(items))
+	severity := models.SeverityInfo
+	for _, item := range items {
+		lines = append(lines, fmt.Sprintf("• %s: %s", item.Notification.Title, item.Notification.Body))
+		if severityRank(item.Notification.Severity) > severityRank(severity) {
+			severity = item.Notification.Severity
+		}
+	}
+	return models.Notification{
+		Title:    fmt.Sprintf("%s (%d건)", title, len(items)),
+		Body:     strings.Join(lines, "\n"),
+		Tag:      "digest",
+		URL:      "/",
+		Severity: severity,
+	}
+}
+
+// 매분 실행: 방해 금지 시간이 끝난 수신자에게 보류된 알림을, 요약 시각이 된 수신자에게 요약을 보냅니다.
+func runNotificationFlush() error {
+	now := time.Now().In(seoul)
+	today := now.Format("20060102")
+
+	delivery.mutex.Lock()
+	delivery.ensureLoaded()
+	type pending struct {
+		recipient    models.Recipient
+		notification models.Notification
+	}
+	var outgoing []pending
+
+	var kept []models.QueuedNotification
+	digestMarked := false
+	for _, r := range delivery.recipients {
+		var released, digest []models.QueuedNotification
+		for _, item := range delivery.queue {
+			if item.RecipientID != r.ID {
+				continue
+			}
+			if item.Digest {
+				digest = append(digest, item)
+			} else {
+				released = append(released, item)
+			}
+		}
+
+		if len(released) > 0 && !inQuietHours(r, now) {
+			outgoing = append(outgoing, pending{r, combineNotifications("밤사이 알림", released)})
+			released = nil
+		}
+		digestDue := false
+		if r.DigestTime != "" && delivery.lastDigest[r.ID] != today {
+			if at, err := parseClock(r.DigestTime); err == nil && now.Hour()*60+now.Minute() >= at {
+				digestDue = !inQuietHours(r, now)
+			}
+		}
+		// 요약 시각이 지나면 보낼 알림이 없어도 오늘 요약은 쓴 것으로 칩니다.
+		// 그래야 요약 시각 뒤에 들어온 알림이 다음 분에 바로 나가지 않고 내일 요약에 묶입니다.
+		if digestDue {
+			if len(digest) > 0 {
+				outgoing = append(outgoing, pending{r, combineNotifications("오늘의 알림 요약", digest)})
+				digest = nil
+			}
+			delivery.lastDigest[r.ID] = today
+			digestMarked = true
+		}
+		kept = append(kept, released...)
+		kept = append(kept, digest...)
+	}
+	// 삭제된 수신자의 알림은 버립니다.
+	changed := len(kept) != len(delivery.queue) || len(outgoing) > 0 || digestMarked
+	delivery.queue = kept
+	if changed {
+		delivery.saveQueue()
+	}
+	delivery.mutex.Unlock()
+
+	for _, p := range outgoing {
+		deliverTo(p.recipient, p.notification)
+	}
+	return nil
+}
+
+func validateRecipient(r models.Recipient) error {
+	if r.ID == "" {
+		return fmt.Errorf("id가 필요합니다")
+	}
+	for _, clock := range []string{r.QuietStart, r.QuietEnd, r.DigestTime} {
+		if clock == "" {
+			continue
+		}
+		if _, err := parseClock(clock); err != nil {
+			return err
+		}
+	}
+	if (r.QuietStart == "") != (r.QuietEnd == "") {
+		return fmt.Errorf("%s: quietStart와 quietEnd는 함께 지정해야 합니다", r.ID)
+	}
+	if len(r.Channels) == 0 {
+		return fmt.Errorf("%s: channels가 비어 있으면 알림을 받을 수 없습니다", r.ID)
+	}
+	for _, channel := range r.Channels {
+		if channel != "push" && channel != "log" {
+			return fmt.Errorf("%s: 지원하지 않는 채널 %q", r.ID, channel)
+		}
+	}
+	switch r.MinSeverity {
+	case "", models.SeverityInfo, models.SeverityWarning, models.SeverityCritical:
+	default:
+		return fmt.Errorf("%s: 잘못된 minSeverity %q", r.ID, r.MinSeverity)
+	}
+	if r.Digest && r.DigestTime == "" {
+		return fmt.Errorf("%s: 요약 모드에는 digestTime이 필요합니다", r.ID)
+	}
+	return nil
+}
+
+// GetRecipients는 수신자별 알림 설정을 JSON으로 반환합니다.
+func GetRecipients(w http.ResponseWriter, r *http.Request) {
+	delivery.mutex.Lock()
+	delivery.ensureLoaded()
+	recipients := append([]models.Recipient(nil), delivery.recipients...)
+	delivery.mutex.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recipients)
+}
+
+// PutRecipients는 수신자 목록 전체를 교체하고 data/recipients.json에 저장합니다.
+// 알림을 받을 사람과 경로를 바꾸는 요청이므로 실내 센서 수집과 같은 INDOOR_TOKEN으로 인증합니다.
+func PutRecipients(w http.ResponseWriter, r *http.Request) {
+	if !indoorAuthorized(r) {
+		http.Error(w, "인증에 실패했습니다.", http.StatusUnauthorized)
+		return
+	}
+	var recipients []models.Recipient
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&recipients); err != nil {
+		http.Error(w, "잘못된 수신자 설정입니다.", http.StatusBadRequest)
+		return
+	}
+	seen := make(map[string]bool)
+	for _, recipient := range recipients {
+		if err := validateRecipient(recipient); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if seen[recipient.ID] {
+			http.Error(w, fmt.Sprintf("중복된 id: %s", recipient.ID), http.StatusBadRequest)
+			return
+		}
+		seen[recipient.ID] = true
+	}
+
+	delivery.mutex.Lock()
+	defer delivery.mutex.Unlock()
+	delivery.ensureLoaded()
+	if err := writeJSONFile(recipientsPath(), recipients); err != nil {
+		log.Printf("수신자 설정 저장 실패: %v", err)
+		http.Error(w, "수신자 설정을 저장할 수 없습니다.", http.StatusInternalServerError)
+		return
+	}
+	delivery.recipients = recipients
+	if len(delivery.recipients) == 0 {
+		delivery.recipients = defaultRecipients()
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/mail"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/korean"
+)
+
+// feedSource는 RSS 2.0 또는 Atom 피드입니다.
+// 피드마다 마지막 ETag/Last-Modified를 기억해 두고 조건부 GET을 보내, 바뀌지 않았으면(304) 지난 기사를 씁니다.
+type feedSource struct {
+	url string
+}
+
+func (s feedSource) Name() string { return s.url }
+
+// 피드 하나의 조건부 GET 상태
+type feedState struct {
+	ETag         string
+	LastModified string
+	Items        []models.NewsItem
+}
+
+var feedStates = struct {
+	feeds map[string]feedState
+	mutex sync.Mutex
+}{feeds: make(map[string]feedState)}
+
+// 피드 응답 본문 최대 크기
+const maxFeedBytes = 5 << 20
+
+func (s feedSource) Fetch() ([]models.NewsItem, error) {
+	req, err := http.NewRequest("GET", s.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("피드 요청 생성 실패: %v", err)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return nil, fmt.Errorf("피드 주소는 http/https여야 합니다")
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, text/xml;q=0.8")
+
+	feedStates.mutex.Lock()
+	state, cached := feedStates.feeds[s.url]
+	feedStates.mutex.Unlock()
+	if cached {
+		if state.ETag != "" {
+			req.Header.Set("If-None-Match", state.ETag)
+		}
+		if state.LastModified != "" {
+			req.Header.Set("If-Modified-Since", state.LastModified)
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("피드 요청 실패: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached {
+		return state.Items, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("피드 응답 코드: %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes))
+	if err != nil {
+		return nil, fmt.Errorf("피드 응답 읽기 실패: %v", err)
+	}
+	// XML 선언에 인코딩이 없으면 응답 헤더의 charset을 따릅니다.
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil && xmlDeclaredCharset(body) == "" {
+		if body, err = decodeFeedCharset(body, params["charset"]); err != nil {
+			return nil, err
+		}
+	}
+	items, err := parseFeed(body)
+	if err != nil {
+		return nil, err
+	}
+
+	feedStates.mutex.Lock()
+	feedStates.feeds[s.url] = feedState{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Items:        items,
+	}
+	feedStates.mutex.Unlock()
+	return items, nil
+}
+
+// RSS 2.0 (<rss><channel><item>)
+type rssDocument struct {
+	Channel struct {
+		Title string `xml:"title"`
+		Items []struct {
+			Title       string `xml:"title"`
+			Link        string `xml:"link"`
+			Description string `xml:"description"`
+			PubDate     string `xml:"pubDate"`
+			GUID        string `xml:"guid"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+// Atom (<feed><entry>)
+type atomDocument struct {
+	Title   string `xml:"title"`
+	Entries []struct {
+		Title string `xml:"title"`
+		Links []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Summary   string `xml:"summary"`
+		Content   string `xml:"content"`
+		Published string `xml:"published"`
+		Updated   string `xml:"updated"`
+	} `xml:"entry"`
+}
+
+// parseFeed는 RSS 2.0과 Atom 피드를 NewsItem으로 바꿉니다. 날짜는 Naver와 같은 RFC 1123 형식으로 맞춥니다.
+func parseFeed(body []byte) ([]models.NewsItem, error) {
+	root, err := feedRootElement(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []models.NewsItem
+	switch root {
+	case "rss":
+		var doc rssDocument
+		if err := decodeFeed(body, &doc); err != nil {
+			return nil, err
+		}
+		source := cleanNewsText(doc.Channel.Title)
+		for _, it := range doc.Channel.Items {
+			link := strings.TrimSpace(it.Link)
+			if link == "" && strings.HasPrefix(it.GUID, "http") {
+				link = strings.TrimSpace(it.GUID)
+			}
+			items = append(items, models.NewsItem{
+				Title:        it.Title,
+				OriginalLink: link,
+				Link:         link,
+				Description:  it.Description,
+				PubDate:      feedDate(it.PubDate),
+				Source:       source,
+			})
+		}
+	case "feed":
+		var doc atomDocument
+		if err := decodeFeed(body, &doc); err != nil {
+			return nil, err
+		}
+		source := cleanNewsText(doc.Title)
+		for _, entry := range doc.Entries {
+			var link string
+			for _, l := range entry.Links {
+				if l.Rel == "" || l.Rel == "alternate" {
+					link = strings.TrimSpace(l.Href)
+					break
+				}
+			}
+			description := entry.Summary
+			if description == "" {
+				description = entry.Content
+			}
+			published := entry.Published
+			if published == "" {
+				published = entry.Updated
+			}
+			items = append(items, models.NewsItem{
+				Title:        entry.Title,
+				OriginalLink: link,
+				Link:         link,
+				Description:  description,
+				PubDate:      feedDate(published),
+				Source:       source,
+			})
+		}
+	default:
+		return nil, fmt.Errorf("RSS/Atom 피드가 아닙니다 (<%s>)", root)
+	}
+	return items, nil
+}
+
+// feedEncoding은 charset 이름에 맞는 인코딩입니다. UTF-8이면 nil입니다.
+// 지역 언론사 피드에 아직 많은 EUC-KR은 CP949(확장 완성형)까지 함께 읽습니다.
+func feedEncoding(charset string) (encoding.Encoding, error) {
+	switch strings.ToLower(strings.TrimSpace(charset)) {
+	case "", "utf-8", "utf8", "us-ascii":
+		return nil, nil
+	case "euc-kr", "euckr", "cp949", "windows-949", "x-windows-949", "ks_c_5601-1987", "ksc5601", "uhc":
+		return korean.EUCKR, nil
+	}
+	return nil, fmt.Errorf("지원하지 않는 문자 인코딩입니다: %s (UTF-8, EUC-KR 피드만 읽을 수 있습니다)", charset)
+}
+
+var xmlEncodingPattern = regexp.MustCompile(`^\x{FEFF}?\s*<\?xml[^>]*?encoding\s*=\s*["']([^"']+)["']`)
+
+// xmlDeclaredCharset은 <?xml ... encoding="..."?>에 적힌 인코딩입니다. 없으면 빈 문자열입니다.
+func xmlDeclaredCharset(body []byte) string {
+	if m := xmlEncodingPattern.FindSubmatch(body); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
+
+// decodeFeedCharset은 charset으로 쓰인 본문을 UTF-8로 바꿉니다.
+func decodeFeedCharset(body []byte, charset string) ([]byte, error) {
+	enc, err := feedEncoding(charset)
+	if err != nil || enc == nil {
+		return body, err
+	}
+	decoded, err := enc.NewDecoder().Bytes(body)
+	if err != nil {
+		return nil, fmt.Errorf("피드 문자 인코딩 변환 실패 (%s): %v", charset, err)
+	}
+	return decoded, nil
+}
+
+func newFeedDecoder(body []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		enc, err := feedEncoding(charset)
+		if err != nil || enc == nil {
+			return input, err
+		}
+		return enc.NewDecoder().Reader(input), nil
+	}
+	return decoder
+}
+
+func decodeFeed(body []byte, v interface{}) error {
+	if err := newFeedDecoder(body).Decode(v); err != nil {
+		return fmt.Errorf("피드 XML 파싱 실패: %v", err)
+	}
+	return nil
+}
+
+// feedRootElement는 문서의 첫 요소 이름(rss, feed ...)입니다.
+func feedRootElement(body []byte) (string, error) {
+	decoder := newFeedDecoder(body)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("피드 XML 파싱 실패: %v", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return strings.ToLower(start.Name.Local), nil
+		}
+	}
+}
+
+// 피드에서 흔히 쓰는 날짜 형식들
+var feedDateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// feedDate는 피드 날짜를 "Mon, 02 Jan 2006 15:04:05 -0700"으로 바꿉니다. 읽을 수 없으면 그대로 둡니다.
+// 시간대가 없으면 한국 시각으로 봅니다.
+func feedDate(s string) string {
+	s = strings.TrimSpace(s)
+	if t, err := mail.ParseDate(s); err == nil {
+		return t.In(seoul).Format(time.RFC1123Z)
+	}
+	for _, layout := range feedDateLayouts {
+		if t, err := time.ParseInLocation(layout, s, seoul); err == nil {
+			return t.In(seoul).Format(time.RFC1123Z)
+		}
+	}
+	return s
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 기상청 체감온도 산출식
+// - 겨울철(10~4월): 기온 10℃ 이하, 풍속 1.3m/s 이상일 때 바람냉각 체감온도
+// - 여름철(5~9월): 기온과 습도로 구한 습구온도(Stull 식)를 이용한 체감온도
+// 불쾌지수는 기온과 습도로 계산합니다.
+
+// windChill은 겨울철 체감온도입니다. 풍속(m/s)은 km/h로 바꿔 씁니다.
+func windChill(tmp, wsd float64) (float64, bool) {
+	if tmp > 10 || wsd < 1.3 {
+		return 0, false
+	}
+	v := math.Pow(wsd*3.6, 0.16)
+	return 13.12 + 0.6215*tmp - 11.37*v + 0.3965*v*tmp, true
+}
+
+// wetBulb는 Stull(2011) 식으로 구한 습구온도입니다.
+func wetBulb(tmp, reh float64) float64 {
+	return tmp*math.Atan(0.151977*math.Sqrt(reh+8.313659)) +
+		math.Atan(tmp+reh) - math.Atan(reh-1.67633) +
+		0.00391838*math.Pow(reh, 1.5)*math.Atan(0.023101*reh) - 4.686035
+}
+
+// summerApparentTemp는 여름철 체감온도입니다.
+func summerApparentTemp(tmp, reh float64) float64 {
+	tw := wetBulb(tmp, reh)
+	return -0.2442 + 0.55399*tw + 0.45535*tmp - 0.0022*tw*tw + 0.00278*tw*tmp + 3.0
+}
+
+// discomfortIndex는 불쾌지수입니다. (68 미만 낮음, 68~75 보통, 75~80 높음, 80 이상 매우 높음)
+func discomfortIndex(tmp, reh float64) float64 {
+	return 1.8*tmp - 0.55*(1-reh/100)*(1.8*tmp-26) + 32
+}
+
+func discomfortLevel(di float64) string {
+	switch {
+	case di >= 80:
+		return "매우 높음"
+	case di >= 75:
+		return "높음"
+	case di >= 68:
+		return "보통"
+	default:
+		return "낮음"
+	}
+}
+
+func isSummerSeason(month time.Month) bool {
+	return month >= time.May && month <= time.September
+}
+
+// applyFeelsLike는 예보 칸에 체감온도와 불쾌지수를 채웁니다.
+// FeelsLike는 계절에 맞는 체감온도이고, 계산할 수 없으면 기온과 같습니다.
+func applyFeelsLike(item *models.WeatherItem) {
+	tmp, ok := numericValue(item.Tmp)
+	if !ok {
+		return
+	}
+	item.FeelsLike = item.Tmp
+	reh, hasReh := numericValue(item.Humidity)
+	wsd, hasWsd := numericValue(item.Wsd)
+
+	month := time.Month(0)
+	if t, err := slotTime(*item); err == nil {
+		month = t.Month()
+	}
+
+	if hasWsd {
+		if v, ok := windChill(tmp, wsd); ok {
+			item.WindChill = fmt.Sprintf("%.0f℃", v)
+		}
+	}
+	if hasReh && isSummerSeason(month) {
+		item.HeatIndex = fmt.Sprintf("%.0f℃", summerApparentTemp(tmp, reh))
+	}
+	// 불쾌지수는 더운 날에만 의미가 있습니다.
+	if hasReh && tmp >= 20 {
+		item.Discomfort = fmt.Sprintf("%.0f", discomfortIndex(tmp, reh))
+	}
+
+	switch {
+	case isSummerSeason(month) && item.HeatIndex != "":
+		item.FeelsLike = item.HeatIndex
+	case !isSummerSeason(month) && item.WindChill != "":
+		item.FeelsLike = item.WindChill
+	}
+}
+
+// feelsLikeValue는 체감온도를 숫자로 돌려줍니다. 체감온도가 없으면 기온을 씁니다.
+func feelsLikeValue(item models.WeatherItem) (float64, bool) {
+	if v, ok := numericValue(item.FeelsLike); ok {
+		return v, true
+	}
+	return numericValue(item.Tmp)
+}
+
+// feelsTempClass는 getTempClass처럼 색상 클래스를 고르되, 체감온도를 기준으로 합니다.
+func feelsTempClass(item models.WeatherItem) string {
+	if item.FeelsLike != "" {
+		return getTempClass(item.FeelsLike)
+	}
+	return getTempClass(item.Tmp)
+}
+
+// feelsLikeNote는 예보 칸 아래에 붙이는 "체감 -7℃" 줄입니다.
+type feelsLikeNote struct {
+	Text  string
+	Title string
+}
+
+// 기온과 1도 이상 다르거나 불쾌지수가 높을 때만 보여줍니다. 보여줄 게 없으면 nil입니다.
+func feelsLikeLine(item models.WeatherItem) *feelsLikeNote {
+	tmp, _ := numericValue(item.Tmp)
+	feels, ok := numericValue(item.FeelsLike)
+	var text string
+	if ok && math.Abs(feels-tmp) >= 1 {
+		text = "체감 " + item.FeelsLike
+	}
+	if di, ok := numericValue(item.Discomfort); ok && di >= 75 {
+		if text != "" {
+			text += " · "
+		}
+		text += "불쾌지수 " + discomfortLevel(di)
+	}
+	if text == "" {
+		return nil
+	}
+	return &feelsLikeNote{
+		Text:  text,
+		Title: fmt.Sprintf("바람냉각 체감온도 %s / 여름철 체감온도 %s / 불쾌지수 %s", orDash(item.WindChill), orDash(item.HeatIndex), orDash(item.Discomfort)),
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import "math"
+
+// 기상청 동네예보 격자(Lambert Conformal Conic) 변환 상수
+const (
+	gridEarthRadius = 6371.00877 // 지구 반경 (km)
+	gridSpacing     = 5.0        // 격자 간격 (km)
+	gridSlat1       = 30.0       // 표준 위도 1
+	gridSlat2       = 60.0       // 표준 위도 2
+	gridOriginLon   = 126.0      // 기준점 경도
+	gridOriginLat   = 38.0       // 기준점 위도
+	gridOriginX     = 43.0       // 기준점 X 좌표
+	gridOriginY     = 136.0      // 기준점 Y 좌표
+
+	// 동네예보가 제공되는 격자 범위 (nx 1~149, ny 1~253)
+	gridMaxX = 149
+	gridMaxY = 253
+)
+
+// validGrid는 nx, ny가 동네예보 격자 범위 안에 있는지 확인합니다.
+func validGrid(nx, ny int) bool {
+	return nx >= 1 && nx <= gridMaxX && ny >= 1 && ny <= gridMaxY
+}
+
+type lccParams struct {
+	re, sn, sf, ro float64
+}
+
+func lambertParams() lccParams {
+	const deg = math.Pi / 180
+	re := gridEarthRadius / gridSpacing
+	slat1, slat2 := gridSlat1*deg, gridSlat2*deg
+	olat := gridOriginLat * deg
+
+	sn := math.Tan(math.Pi*0.25+slat2*0.5) / math.Tan(math.Pi*0.25+slat1*0.5)
+	sn = math.Log(math.Cos(slat1)/math.Cos(slat2)) / math.Log(sn)
+	sf := math.Tan(math.Pi*0.25 + slat1*0.5)
+	sf = math.Pow(sf, sn) * math.Cos(slat1) / sn
+	ro := math.Tan(math.Pi*0.25 + olat*0.5)
+	ro = re * sf / math.Pow(ro, sn)
+	return lccParams{re: re, sn: sn, sf: sf, ro: ro}
+}
+
+// latLonToGrid는 위경도를 동네예보 격자 좌표(nx, ny)로 변환합니다. (기상청 제공 공식)
+func latLonToGrid(lat, lon float64) (int, int) {
+	const deg = math.Pi / 180
+	p := lambertParams()
+
+	ra := math.Tan(math.Pi*0.25 + lat*deg*0.5)
+	ra = p.re * p.sf / math.Pow(ra, p.sn)
+	theta := lon*deg - gridOriginLon*deg
+	if theta > math.Pi {
+		theta -= 2 * math.Pi
+	}
+	if theta < -math.Pi {
+		theta += 2 * math.Pi
+	}
+	theta *= p.sn
+
+	x := math.Floor(ra*math.Sin(theta) + gridOriginX + 0.5)
+	y := math.Floor(p.ro - ra*math.Cos(theta) + gridOriginY + 0.5)
+	return int(x), int(y)
+}
+
+// gridToLatLon은 격자 좌표를 격자 중심의 위경도로 되돌립니다. (latLonToGrid의 역변환)
+func gridToLatLon(nx, ny int) (lat, lon float64) {
+	const deg = math.Pi / 180
+	p := lambertParams()
+
+	xn := float64(nx) - gridOriginX
+	yn := p.ro - float64(ny) + gridOriginY
+	ra := math.Sqrt(xn*xn + yn*yn)
+	if p.sn < 0 {
+		ra = -ra
+	}
+	alat := math.Pow(p.re*p.sf/ra, 1/p.sn)
+	alat = 2*math.Atan(alat) - math.Pi*0.5
+
+	var theta float64
+	switch {
+	case math.Abs(xn) <= 0:
+		theta = 0
+	case math.Abs(yn) <= 0:
+		theta = math.Copysign(math.Pi*0.5, xn)
+	default:
+		theta = math.Atan2(xn, yn)
+	}
+	alon := theta/p.sn + gridOriginLon*deg
+	return alat / deg, alon / deg
+}
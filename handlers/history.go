@@ -0,0 +1,393 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// timeSeriesStore는 data/history/<name>/YYYYMMDD.jsonl 하루 단위 파일에 기록을 한 줄씩 덧붙이는 저장소입니다.
+// 보관 기간이 지난 날짜 파일은 통째로 지웁니다.
+type timeSeriesStore struct {
+	name          string
+	retentionEnv  string // 보관 일수를 바꾸는 환경변수
+	retentionDays int
+	mutex         sync.Mutex
+}
+
+var (
+	forecastHistory    = &timeSeriesStore{name: "forecast", retentionEnv: "HISTORY_FORECAST_DAYS", retentionDays: 35}
+	observationHistory = &timeSeriesStore{name: "observation", retentionEnv: "HISTORY_OBSERVATION_DAYS", retentionDays: 400}
+)
+
+func (s *timeSeriesStore) dir() string { return filepath.Join(dataDir(), "history", s.name) }
+
+func (s *timeSeriesStore) segmentPath(day time.Time) string {
+	return filepath.Join(s.dir(), day.In(seoul).Format("20060102")+".jsonl")
+}
+
+func (s *timeSeriesStore) retention() int {
+	if days, err := strconv.Atoi(os.Getenv(s.retentionEnv)); err == nil && days > 0 {
+		return days
+	}
+	return s.retentionDays
+}
+
+// append는 day 파일 끝에 기록들을 JSON 한 줄씩 덧붙입니다.
+func (s *timeSeriesStore) append(day time.Time, records []interface{}) error {
+	if len(records) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := os.MkdirAll(s.dir(), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.segmentPath(day), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// scan은 from~to 날짜 파일의 모든 줄을 fn에 넘깁니다. 없는 날짜는 건너뜁니다.
+// fn은 저장소 잠금을 잡은 채로 호출되므로 fn 안에서 같은 저장소를 다시 쓰면 안 됩니다.
+// 깨진 줄(쓰는 도중 꺼진 경우 등)은 무시합니다.
+func (s *timeSeriesStore) scan(from, to time.Time, fn func(line []byte)) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	from, to = from.In(seoul), to.In(seoul)
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, seoul)
+	for ; !day.After(to); day = day.AddDate(0, 0, 1) {
+		f, err := os.Open(s.segmentPath(day))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			if json.Valid(scanner.Bytes()) {
+				fn(scanner.Bytes())
+			}
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// prune은 보관 기간이 지난 날짜 파일을 지우고 지운 개수를 반환합니다.
+func (s *timeSeriesStore) prune(now time.Time) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	entries, err := os.ReadDir(s.dir())
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	cutoff := now.In(seoul).AddDate(0, 0, -s.retention()).Format("20060102")
+	removed := 0
+	for _, entry := range entries {
+		day := strings.TrimSuffix(entry.Name(), ".jsonl")
+		if entry.IsDir() || day == entry.Name() || day >= cutoff {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir(), entry.Name())); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// 이미 저장한 (격자, 발표/관측 시각). 같은 발표분을 다시 받아와도 한 번만 저장합니다.
+type recordedKeys struct {
+	seen  map[string]bool
+	mutex sync.Mutex
+}
+
+var (
+	recordedForecasts    = &recordedKeys{seen: make(map[string]bool)}
+	recordedObservations = &recordedKeys{seen: make(map[string]bool)}
+)
+
+// claim은 key를 처음 보는 경우에만 true를 반환합니다. exists는 재시작 직후처럼
+// 메모리에 기록이 없을 때 파일에 이미 있는지 확인합니다.
+func (k *recordedKeys) claim(key string, exists func() bool) bool {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	if k.seen[key] {
+		return false
+	}
+	k.seen[key] = true
+	return !exists()
+}
+
+// recordForecastHistory는 받아온 예보를 발표 시각과 격자별로 저장합니다.
+func recordForecastHistory(loc models.Location, rawData []models.WeatherItemToReturn) error {
+	if len(rawData) == 0 {
+		return nil
+	}
+	grid := locationKey(loc)
+	base, err := time.ParseInLocation("200601021504", rawData[0].BaseDate+rawData[0].BaseTime, seoul)
+	if err != nil {
+		return fmt.Errorf("발표 시각 파싱 실패: %v", err)
+	}
+
+	key := grid + "|" + base.Format(time.RFC3339)
+	isNew := recordedForecasts.claim(key, func() bool {
+		found := false
+		forecastHistory.scan(base, base, func(line []byte) {
+			var record models.ForecastRecord
+			if json.Unmarshal(line, &record) == nil && record.Grid == grid && record.BaseTime.Equal(base) {
+				found = true
+			}
+		})
+		return found
+	})
+	if !isNew {
+		return nil
+	}
+
+	bySlot := make(map[string]*models.ForecastRecord)
+	var slots []string
+	now := time.Now()
+	for _, item := range rawData {
+		slot := item.Date + item.Time
+		if bySlot[slot] == nil {
+			fcst, err := time.ParseInLocation("200601021504", slot, seoul)
+			if err != nil {
+				continue
+			}
+			bySlot[slot] = &models.ForecastRecord{Grid: grid, BaseTime: base, FcstTime: fcst, Values: make(map[string]string), FetchedAt: now}
+			slots = append(slots, slot)
+		}
+		bySlot[slot].Values[item.Category] = item.Value
+	}
+	sort.Strings(slots)
+	records := make([]interface{}, 0, len(slots))
+	for _, slot := range slots {
+		records = append(records, bySlot[slot])
+	}
+	return forecastHistory.append(base, records)
+}
+
+// recordObservation은 관측값 하나를 저장합니다. 이미 저장한 시각이면 false를 반환합니다.
+func recordObservation(record models.ObservationRecord) (bool, error) {
+	key := record.Grid + "|" + record.Time.Format(time.RFC3339)
+	isNew := recordedObservations.claim(key, func() bool {
+		_, found := observationAt(record.Grid, record.Time)
+		return found
+	})
+	if !isNew {
+		return false, nil
+	}
+	return true, observationHistory.append(record.Time, []interface{}{record})
+}
+
+// forecastsFor는 target 시각에 대해 발표 시각마다 나온 예보를 발표 순서대로 반환합니다.
+// 단기예보는 최대 3일 앞까지 나오므로 target 3일 전부터의 발표분을 찾습니다.
+func forecastsFor(grid string, target time.Time) ([]models.ForecastHistoryEntry, error) {
+	// JSON에서 읽은 time.Time은 위치 정보가 제각각이라 Unix 초를 키로 씁니다.
+	byBase := make(map[int64]models.ForecastHistoryEntry)
+	err := forecastHistory.scan(target.AddDate(0, 0, -3), target, func(line []byte) {
+		var record models.ForecastRecord
+		if json.Unmarshal(line, &record) != nil || record.Grid != grid || !record.FcstTime.Equal(target) {
+			return
+		}
+		byBase[record.BaseTime.Unix()] = models.ForecastHistoryEntry{
+			BaseTime:  record.BaseTime.In(seoul),
+			LeadHours: int(record.FcstTime.Sub(record.BaseTime).Hours()),
+			Values:    record.Values,
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]models.ForecastHistoryEntry, 0, len(byBase))
+	for _, entry := range byBase {
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].BaseTime.Before(result[j].BaseTime)
+	})
+	return result, nil
+}
+
+// scanForecasts는 발표 시각이 [from, to]인 예보 기록을 모두 fn에 넘깁니다.
+func scanForecasts(grid string, from, to time.Time, fn func(models.ForecastRecord)) error {
+	return forecastHistory.scan(from, to, func(line []byte) {
+		var record models.ForecastRecord
+		if json.Unmarshal(line, &record) != nil || record.Grid != grid {
+			return
+		}
+		if record.BaseTime.Before(from) || record.BaseTime.After(to) {
+			return
+		}
+		fn(record)
+	})
+}
+
+// observationsBetween은 [from, to] 사이의 관측값을 시간순으로 반환합니다.
+func observationsBetween(grid string, from, to time.Time) ([]models.ObservationRecord, error) {
+	byTime := make(map[int64]models.ObservationRecord)
+	err := observationHistory.scan(from, to, func(line []byte) {
+		var record models.ObservationRecord
+		if json.Unmarshal(line, &record) != nil || record.Grid != grid {
+			return
+		}
+		if record.Time.Before(from) || record.Time.After(to) {
+			return
+		}
+		record.Time = record.Time.In(seoul)
+		byTime[record.Time.Unix()] = record
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]models.ObservationRecord, 0, len(byTime))
+	for _, record := range byTime {
+		result = append(result, record)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+	return result, nil
+}
+
+func observationAt(grid string, t time.Time) (models.ObservationRecord, bool) {
+	records, err := observationsBetween(grid, t, t)
+	if err != nil || len(records) == 0 {
+		return models.ObservationRecord{}, false
+	}
+	return records[0], true
+}
+
+// 매일 새벽 보관 기간이 지난 이력을 지웁니다.
+func runHistoryRetention() error {
+	now := time.Now()
+	for _, store := range []*timeSeriesStore{forecastHistory, observationHistory} {
+		removed, err := store.prune(now)
+		if err != nil {
+			return fmt.Errorf("%s 이력 정리 실패: %v", store.name, err)
+		}
+		if removed > 0 {
+			log.Printf("%s 이력 %d일치 삭제 (보관 %d일)", store.name, removed, store.retention())
+		}
+	}
+	return nil
+}
+
+// 쿼리의 grid(nx,ny)를 읽습니다. 없으면 집 격자입니다.
+func gridFromRequest(r *http.Request) (string, error) {
+	grid := r.URL.Query().Get("grid")
+	if grid == "" {
+		return locationKey(homeLocation()), nil
+	}
+	loc, err := parseGrid(grid, "grid")
+	if err != nil {
+		return "", err
+	}
+	return locationKey(loc), nil
+}
+
+// GetForecastHistory는 한 시각에 대해 발표 시각마다 어떤 예보가 나왔는지 반환합니다.
+// 예: /api/history/forecasts?date=20261018&time=1500[&grid=77,131]
+func GetForecastHistory(w http.ResponseWriter, r *http.Request) {
+	grid, err := gridFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	q := r.URL.Query()
+	target, err := time.ParseInLocation("200601021504", q.Get("date")+q.Get("time"), seoul)
+	if err != nil {
+		http.Error(w, "date(YYYYMMDD)와 time(HHMM)이 필요합니다.", http.StatusBadRequest)
+		return
+	}
+	entries, err := forecastsFor(grid, target)
+	if err != nil {
+		log.Printf("예보 이력 조회 실패: %v", err)
+		http.Error(w, "예보 이력을 읽을 수 없습니다.", http.StatusInternalServerError)
+		return
+	}
+
+	actual, ok := observationAt(grid, target)
+	response := map[string]interface{}{
+		"grid":      grid,
+		"time":      target,
+		"forecasts": entries,
+	}
+	if ok {
+		response["observed"] = actual.Values
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetObservationHistory는 실제 관측값(초단기실황)을 반환합니다.
+// 예: /api/history/observations?date=20261018[&time=1500][&grid=77,131]
+func GetObservationHistory(w http.ResponseWriter, r *http.Request) {
+	grid, err := gridFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	q := r.URL.Query()
+	day, err := time.ParseInLocation("20060102", q.Get("date"), seoul)
+	if err != nil {
+		http.Error(w, "date(YYYYMMDD)가 필요합니다.", http.StatusBadRequest)
+		return
+	}
+	from, to := day, day.Add(24*time.Hour-time.Second)
+	if clock := q.Get("time"); clock != "" {
+		at, err := time.ParseInLocation("200601021504", q.Get("date")+clock, seoul)
+		if err != nil {
+			http.Error(w, "time은 HHMM 형식이어야 합니다.", http.StatusBadRequest)
+			return
+		}
+		from, to = at, at
+	}
+	records, err := observationsBetween(grid, from, to)
+	if err != nil {
+		log.Printf("관측 이력 조회 실패: %v", err)
+		http.Error(w, "관측 이력을 읽을 수 없습니다.", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(records)
+}
@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 대체공휴일 규칙
+const (
+	noSubstitute      = iota
+	substituteWeekend // 토요일·일요일 또는 다른 공휴일과 겹치면
+	substituteSunday  // 일요일 또는 다른 공휴일과 겹치면 (설날·추석 연휴)
+)
+
+// holidayRule은 해마다 돌아오는 공휴일입니다. Lunar이면 Month/Day는 음력입니다.
+// Length는 연휴 일수(설날·추석은 전날부터 3일), SubstituteSince는 대체공휴일이 적용되기 시작한 해입니다.
+type holidayRule struct {
+	Name            string
+	Month, Day      int
+	Lunar           bool
+	Length          int
+	Substitute      int
+	SubstituteSince int
+}
+
+var holidayRules = []holidayRule{
+	{Name: "신정", Month: 1, Day: 1, Length: 1},
+	{Name: "설날", Month: 1, Day: 1, Lunar: true, Length: 3, Substitute: substituteSunday, SubstituteSince: 2014},
+	{Name: "삼일절", Month: 3, Day: 1, Length: 1, Substitute: substituteWeekend, SubstituteSince: 2022},
+	{Name: "어린이날", Month: 5, Day: 5, Length: 1, Substitute: substituteWeekend, SubstituteSince: 2014},
+	{Name: "부처님오신날", Month: 4, Day: 8, Lunar: true, Length: 1, Substitute: substituteWeekend, SubstituteSince: 2023},
+	{Name: "현충일", Month: 6, Day: 6, Length: 1},
+	{Name: "광복절", Month: 8, Day: 15, Length: 1, Substitute: substituteWeekend, SubstituteSince: 2021},
+	{Name: "추석", Month: 8, Day: 15, Lunar: true, Length: 3, Substitute: substituteSunday, SubstituteSince: 2014},
+	{Name: "개천절", Month: 10, Day: 3, Length: 1, Substitute: substituteWeekend, SubstituteSince: 2021},
+	{Name: "한글날", Month: 10, Day: 9, Length: 1, Substitute: substituteWeekend, SubstituteSince: 2021},
+	{Name: "기독탄신일", Month: 12, Day: 25, Length: 1, Substitute: substituteWeekend, SubstituteSince: 2023},
+}
+
+// 선거일이나 임시공휴일처럼 해마다 정해지는 날은 data/holidays.json에 적습니다.
+// [{"date": "20260603", "name": "전국동시지방선거"}]
+func extraHolidaysPath() string { return dataPath("holidays.json") }
+
+// ruleDates는 그해 공휴일 규칙에 해당하는 날짜들입니다. (연휴는 전날, 당일, 다음 날)
+func ruleDates(rule holidayRule, year int) []time.Time {
+	var center time.Time
+	if rule.Lunar {
+		var ok bool
+		if center, ok = lunarToSolar(year, rule.Month, rule.Day, false); !ok {
+			return nil
+		}
+	} else {
+		center = time.Date(year, time.Month(rule.Month), rule.Day, 0, 0, 0, 0, seoul)
+	}
+	if rule.Length == 1 {
+		return []time.Time{center}
+	}
+	return []time.Time{center.AddDate(0, 0, -1), center, center.AddDate(0, 0, 1)}
+}
+
+// 연휴는 "설날 전날", "설날", "설날 다음 날"로 부릅니다.
+func holidayLabel(rule holidayRule, i int) string {
+	switch {
+	case rule.Length == 1 || i == 1:
+		return rule.Name
+	case i == 0:
+		return rule.Name + " 전날"
+	default:
+		return rule.Name + " 다음 날"
+	}
+}
+
+var holidayCache = struct {
+	years map[int][]models.Holiday
+	mutex sync.Mutex
+}{years: make(map[int][]models.Holiday)}
+
+// holidaysOf는 그해 공휴일과 대체공휴일을 날짜순으로 반환합니다.
+func holidaysOf(year int) []models.Holiday {
+	holidayCache.mutex.Lock()
+	defer holidayCache.mutex.Unlock()
+	if cached, ok := holidayCache.years[year]; ok {
+		return cached
+	}
+
+	var result []models.Holiday
+	taken := make(map[string]int) // 날짜 → 그날 공휴일 수
+	add := func(day time.Time, name string, substitute bool) {
+		date := day.Format("20060102")
+		result = append(result, models.Holiday{Date: date, Name: name, Substitute: substitute})
+		taken[date]++
+	}
+
+	// 음력 공휴일은 양력 연도를 넘나들 수 있어 그해에 들어오는 날만 남깁니다.
+	dates := make([][]time.Time, len(holidayRules))
+	for i, rule := range holidayRules {
+		for _, day := range ruleDates(rule, year) {
+			if day.Year() == year {
+				dates[i] = append(dates[i], day)
+			}
+		}
+		for j, day := range dates[i] {
+			add(day, holidayLabel(rule, j), false)
+		}
+	}
+
+	var extra []models.Holiday
+	if err := readJSONFile(extraHolidaysPath(), &extra); err != nil {
+		log.Printf("추가 공휴일 불러오기 실패: %v", err)
+	}
+	for _, h := range extra {
+		if len(h.Date) == 8 && h.Date[:4] == fmt.Sprint(year) {
+			result = append(result, h)
+			taken[h.Date]++
+		}
+	}
+
+	// 대체공휴일: 겹친 날 수만큼, 마지막 날 다음의 첫 비공휴일(평일)로 정합니다.
+	// 두 공휴일이 같은 날 겹쳐도 대체공휴일은 하루만 생깁니다. (2025년 어린이날·부처님오신날)
+	covered := make(map[string]bool)
+	for i, rule := range holidayRules {
+		if rule.Substitute == noSubstitute || year < rule.SubstituteSince || len(dates[i]) == 0 {
+			continue
+		}
+		overlaps := 0
+		for _, day := range dates[i] {
+			date := day.Format("20060102")
+			weekend := day.Weekday() == time.Sunday ||
+				(rule.Substitute == substituteWeekend && day.Weekday() == time.Saturday)
+			if (weekend || taken[date] > 1) && !covered[date] {
+				covered[date] = true
+				overlaps++
+			}
+		}
+		next := dates[i][len(dates[i])-1]
+		for ; overlaps > 0; overlaps-- {
+			for {
+				next = next.AddDate(0, 0, 1)
+				if next.Weekday() != time.Saturday && next.Weekday() != time.Sunday && taken[next.Format("20060102")] == 0 {
+					break
+				}
+			}
+			add(next, "대체공휴일("+rule.Name+")", true)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Date < result[j].Date })
+	holidayCache.years[year] = result
+	return result
+}
+
+// holidaysOn은 그날의 공휴일 이름들입니다.
+func holidaysOn(day time.Time) []models.Holiday {
+	date := day.Format("20060102")
+	var result []models.Holiday
+	for _, h := range holidaysOf(day.Year()) {
+		if h.Date == date {
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
+// dayInfo는 머리글에 보여줄 양력/음력 날짜, 공휴일, 절기를 모읍니다.
+func dayInfo(day time.Time) models.DayInfo {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, seoul)
+	info := models.DayInfo{
+		Date:     day.Format("20060102"),
+		Weekday:  koreanWeekdays[day.Weekday()],
+		Lunar:    solarToLunar(day),
+		Holidays: holidaysOn(day),
+	}
+	if info.Holidays == nil {
+		info.Holidays = []models.Holiday{}
+	}
+	info.IsHoliday = day.Weekday() == time.Sunday || len(info.Holidays) > 0
+	info.SolarTerm, info.SolarTermAt = currentSolarTerm(day)
+	_, _, info.SolarTermToday = solarTermOn(day)
+	return info
+}
+
+// "음력 8월 26일", "음력 윤6월 3일"
+func lunarText(d models.LunarDate) string {
+	leap := ""
+	if d.Leap {
+		leap = "윤"
+	}
+	return fmt.Sprintf("음력 %s%d월 %d일", leap, d.Month, d.Day)
+}
+
+// GetDayInfoJSON은 날짜의 음력, 공휴일, 절기를 JSON으로 반환합니다. (?date=20060102, 없으면 오늘)
+// ?year=2026이면 그해 공휴일 목록을 반환합니다.
+func GetDayInfoJSON(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if year := r.URL.Query().Get("year"); year != "" {
+		y, err := strconv.Atoi(year)
+		if err != nil || y < 1900 || y > 2100 {
+			http.Error(w, "year는 1900~2100 사이여야 합니다", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(holidaysOf(y))
+		return
+	}
+	day, err := astronomyDay(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	json.NewEncoder(w).Encode(dayInfo(day))
+}
+
+// GetDateHeader는 머리글의 날짜 줄을 반환합니다.
+// "10월 17일 (토) · 음력 8월 26일 · 한로"
+func GetDateHeader(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	day, err := astronomyDay(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	info := dayInfo(day)
+
+	class := "date-line"
+	switch {
+	case info.IsHoliday:
+		class += " holiday"
+	case day.Weekday() == time.Saturday:
+		class += " saturday"
+	}
+	type part struct{ Class, Text string }
+	parts := []part{
+		{"solar-date", fmt.Sprintf("%d월 %d일 (%s)", day.Month(), day.Day(), string([]rune(info.Weekday)[:1]))},
+		{"lunar-date", lunarText(info.Lunar)},
+	}
+	for _, h := range info.Holidays {
+		parts = append(parts, part{"holiday-name", h.Name})
+	}
+	if info.SolarTerm != "" {
+		term := info.SolarTerm
+		if info.SolarTermToday {
+			term = fmt.Sprintf("오늘 %s (%s)", info.SolarTerm, info.SolarTermAt.Format("15:04"))
+		}
+		parts = append(parts, part{"solar-term", term})
+	}
+	renderFragment(w, "date-header", struct {
+		Class string
+		Parts []part
+	}{class, parts})
+}
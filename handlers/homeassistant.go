@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// MQTT_BROKER가 설정되어 있을 때만 만들어집니다.
+var mqttBroker *mqttClient
+
+// 앞으로 몇 시간의 예보를 forecast 토픽에 넣을지
+const mqttForecastHours = 6
+
+var mqttPublishState struct {
+	lastPublish time.Time
+	lastError   string
+	mutex       sync.Mutex
+}
+
+func mqttTopicPrefix() string {
+	if prefix := os.Getenv("MQTT_TOPIC_PREFIX"); prefix != "" {
+		return prefix
+	}
+	return "weather-reminder"
+}
+
+func mqttDiscoveryPrefix() string {
+	if prefix := os.Getenv("MQTT_DISCOVERY_PREFIX"); prefix != "" {
+		return prefix
+	}
+	return "homeassistant"
+}
+
+func mqttAvailabilityTopic() string { return mqttTopicPrefix() + "/status" }
+
+var nonIDChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// Home Assistant 디스커버리 토픽과 unique_id에 쓰는 노드 ID
+func mqttNodeID() string {
+	return nonIDChars.ReplaceAllString(mqttBroker.config.ClientID, "_")
+}
+
+// haEntity는 디스커버리로 등록할 센서 하나입니다. 값은 모두 state 토픽의 JSON에서 꺼냅니다.
+type haEntity struct {
+	Component   string // sensor, binary_sensor
+	Key         string
+	Name        string
+	Unit        string
+	DeviceClass string
+	Icon        string
+	Attributes  string // json_attributes_topic으로 쓸 하위 토픽
+}
+
+var haEntities = []haEntity{
+	{Component: "sensor", Key: "temperature", Name: "기온", Unit: "°C", DeviceClass: "temperature", Attributes: "forecast"},
+	{Component: "sensor", Key: "humidity", Name: "습도", Unit: "%", DeviceClass: "humidity"},
+	{Component: "sensor", Key: "pop", Name: "강수확률", Unit: "%", Icon: "mdi:weather-rainy"},
+	{Component: "sensor", Key: "sky", Name: "하늘 상태", Icon: "mdi:weather-partly-cloudy"},
+	{Component: "sensor", Key: "precipitation", Name: "강수 형태", Icon: "mdi:weather-pouring"},
+	{Component: "sensor", Key: "next_max_pop", Name: "6시간 최대 강수확률", Unit: "%", Icon: "mdi:umbrella-outline"},
+	{Component: "sensor", Key: "next_min_temp", Name: "6시간 최저 기온", Unit: "°C", DeviceClass: "temperature"},
+	{Component: "sensor", Key: "next_max_temp", Name: "6시간 최고 기온", Unit: "°C", DeviceClass: "temperature"},
+	{Component: "sensor", Key: "reminder_count", Name: "알림", Icon: "mdi:bell-alert", Attributes: "reminders"},
+	{Component: "binary_sensor", Key: "umbrella", Name: "우산 필요", Icon: "mdi:umbrella"},
+	{Component: "binary_sensor", Key: "warning", Name: "날씨 경고", DeviceClass: "safety"},
+}
+
+func haDiscoveryConfig(e haEntity) map[string]interface{} {
+	node := mqttNodeID()
+	prefix := mqttTopicPrefix()
+	config := map[string]interface{}{
+		"name":               e.Name,
+		"unique_id":          node + "_" + e.Key,
+		"object_id":          node + "_" + e.Key,
+		"state_topic":        prefix + "/state",
+		"value_template":     fmt.Sprintf("{{ value_json.%s }}", e.Key),
+		"availability_topic": mqttAvailabilityTopic(),
+		"device": map[string]interface{}{
+			"identifiers":  []string{node},
+			"name":         fmt.Sprintf("날씨 알리미 (%s)", homeLocation().Name),
+			"manufacturer": "weather-reminder",
+			"model":        "기상청 단기예보",
+		},
+	}
+	if e.Component == "sensor" && e.Unit != "" {
+		config["unit_of_measurement"] = e.Unit
+		config["state_class"] = "measurement"
+	}
+	if e.Component == "binary_sensor" {
+		config["payload_on"] = "ON"
+		config["payload_off"] = "OFF"
+	}
+	if e.DeviceClass != "" {
+		config["device_class"] = e.DeviceClass
+	}
+	if e.Icon != "" {
+		config["icon"] = e.Icon
+	}
+	if e.Attributes != "" {
+		config["json_attributes_topic"] = prefix + "/" + e.Attributes
+	}
+	return config
+}
+
+func publishJSON(topic string, v interface{}) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return mqttBroker.Publish(topic, payload, true)
+}
+
+// publishDiscovery는 센서 설정을 retain으로 발행해 Home Assistant에 기기가 자동으로 나타나게 합니다.
+func publishDiscovery() error {
+	for _, e := range haEntities {
+		topic := fmt.Sprintf("%s/%s/%s/%s/config", mqttDiscoveryPrefix(), e.Component, mqttNodeID(), e.Key)
+		if err := publishJSON(topic, haDiscoveryConfig(e)); err != nil {
+			return fmt.Errorf("디스커버리 발행 실패 (%s): %v", e.Key, err)
+		}
+	}
+	return nil
+}
+
+func onOff(b bool) string {
+	if b {
+		return "ON"
+	}
+	return "OFF"
+}
+
+// 숫자로 읽을 수 없는 값은 JSON null로 보냅니다.
+func numericOrNil(s string) interface{} {
+	if v, ok := numericValue(s); ok {
+		return v
+	}
+	return nil
+}
+
+func precipitationText(pty string) string {
+	if pty == "none" {
+		return "없음"
+	}
+	return precipWord(pty)
+}
+
+// publishWeatherState는 현재/앞으로 몇 시간의 예보와 알림을 retain 토픽으로 발행합니다.
+func publishWeatherState() error {
+	allWeather, err := fetchAndCacheWeather()
+	if err != nil {
+		return err
+	}
+	now := time.Now().In(seoul)
+	upcoming := forecastWindow(allWeather, now, mqttForecastHours*time.Hour)
+	reminders := activeReminders.list()
+
+	state := map[string]interface{}{
+		"updated_at":     now.Format(time.RFC3339),
+		"reminder_count": len(reminders),
+	}
+	var hours []map[string]interface{}
+	umbrella := false
+	var maxPop float64
+	for i, item := range upcoming {
+		if i == 0 {
+			state["temperature"] = numericOrNil(item.Tmp)
+			state["humidity"] = numericOrNil(item.Humidity)
+			state["pop"] = numericOrNil(item.Pop)
+			state["sky"] = skyWord(item.Sky)
+			state["precipitation"] = precipitationText(item.Pty)
+		}
+		pop, _ := numericValue(item.Pop)
+		if pop > maxPop {
+			maxPop = pop
+		}
+		umbrella = umbrella || item.Pty != "none" || pop >= 60
+		t, _ := slotTime(item)
+		hours = append(hours, map[string]interface{}{
+			"time":          t.Format(time.RFC3339),
+			"temperature":   numericOrNil(item.Tmp),
+			"humidity":      numericOrNil(item.Humidity),
+			"pop":           numericOrNil(item.Pop),
+			"sky":           skyWord(item.Sky),
+			"precipitation": precipitationText(item.Pty),
+		})
+	}
+	state["next_max_pop"] = maxPop
+	if minItem, maxItem := tempExtremes(upcoming); minItem != nil {
+		state["next_min_temp"] = numericOrNil(minItem.Tmp)
+		state["next_max_temp"] = numericOrNil(maxItem.Tmp)
+	}
+	state["umbrella"] = onOff(umbrella)
+
+	warning := false
+	for _, r := range reminders {
+		warning = warning || severityRank(r.Severity) >= severityRank(models.SeverityWarning)
+	}
+	state["warning"] = onOff(warning)
+
+	prefix := mqttTopicPrefix()
+	if err := publishJSON(prefix+"/state", state); err != nil {
+		return err
+	}
+	if err := publishJSON(prefix+"/forecast", map[string]interface{}{"hours": hours}); err != nil {
+		return err
+	}
+	if reminders == nil {
+		reminders = []models.Reminder{}
+	}
+	return publishJSON(prefix+"/reminders", map[string]interface{}{"reminders": reminders})
+}
+
+// runMQTTPublish는 스케줄러와 알림 평가 뒤에 호출됩니다. MQTT가 설정되지 않았으면 아무것도 하지 않습니다.
+func runMQTTPublish() error {
+	if mqttBroker == nil {
+		return nil
+	}
+	err := publishWeatherState()
+
+	mqttPublishState.mutex.Lock()
+	defer mqttPublishState.mutex.Unlock()
+	if err != nil {
+		mqttPublishState.lastError = err.Error()
+		return fmt.Errorf("MQTT 발행 실패: %v", err)
+	}
+	mqttPublishState.lastPublish = time.Now()
+	mqttPublishState.lastError = ""
+	return nil
+}
+
+// 연결될 때마다 온라인 상태, 디스커버리, 현재 값을 다시 발행합니다.
+func onMQTTConnect() {
+	if err := mqttBroker.Publish(mqttAvailabilityTopic(), []byte("online"), true); err != nil {
+		log.Printf("MQTT 상태 발행 실패: %v", err)
+		return
+	}
+	if err := publishDiscovery(); err != nil {
+		log.Println(err)
+	}
+	if err := runMQTTPublish(); err != nil {
+		log.Println(err)
+	}
+}
+
+// StartMQTT는 MQTT_BROKER가 설정되어 있으면 브로커에 연결하고 10분마다 예보를 발행합니다.
+func StartMQTT() {
+	config, ok := mqttConfigFromEnv()
+	if !ok {
+		return
+	}
+	config.WillTopic = mqttAvailabilityTopic()
+	config.WillPayload = "offline"
+	mqttBroker = newMQTTClient(config)
+	mqttBroker.OnConnect = onMQTTConnect
+
+	// Home Assistant가 재시작하면 birth 메시지(online)를 보냅니다. 그때 디스커버리를 다시 발행합니다.
+	mqttBroker.Subscribe(mqttDiscoveryPrefix()+"/status", func(topic string, payload []byte) {
+		if string(payload) != "online" {
+			return
+		}
+		if err := publishDiscovery(); err != nil {
+			log.Println(err)
+		}
+		if err := runMQTTPublish(); err != nil {
+			log.Println(err)
+		}
+	})
+	subscribeIndoorMQTT()
+	go mqttBroker.Run()
+
+	job := &Job{
+		Name:        "mqtt-publish",
+		Description: "MQTT로 예보와 알림 발행",
+		Spec:        "*/10 * * * *",
+		Run:         runMQTTPublish,
+	}
+	if err := scheduler.AddJob(job); err != nil {
+		log.Println(err)
+	}
+	log.Printf("MQTT 발행 시작: %s (토픽 %s/#)", config.Broker, mqttTopicPrefix())
+}
+
+// GetMQTTStatus는 MQTT 연결 상태와 마지막 발행 시각을 JSON으로 반환합니다.
+func GetMQTTStatus(w http.ResponseWriter, r *http.Request) {
+	status := map[string]interface{}{"enabled": mqttBroker != nil}
+	if mqttBroker != nil {
+		connected, lastError := mqttBroker.Status()
+		mqttPublishState.mutex.Lock()
+		status["broker"] = mqttBroker.config.Broker
+		status["connected"] = connected
+		status["connectionError"] = lastError
+		status["topicPrefix"] = mqttTopicPrefix()
+		if !mqttPublishState.lastPublish.IsZero() {
+			status["lastPublish"] = mqttPublishState.lastPublish
+		}
+		status["publishError"] = mqttPublishState.lastError
+		mqttPublishState.mutex.Unlock()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,559 @@
+package handlers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// icsProperty는 "DTSTART;TZID=Asia/Seoul:20261017T140000" 같은 내용 줄 하나입니다.
+type icsProperty struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// icsComponent는 BEGIN:...END:... 블록(VCALENDAR, VEVENT, VTIMEZONE ...)입니다.
+type icsComponent struct {
+	Name       string
+	Props      []icsProperty
+	Components []*icsComponent
+}
+
+func (c *icsComponent) prop(name string) (icsProperty, bool) {
+	for _, p := range c.Props {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return icsProperty{}, false
+}
+
+// RFC 5545 3.1: CRLF 뒤에 공백/탭으로 시작하는 줄은 앞 줄에 이어 붙입니다.
+func unfoldICSLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+func parseICSProperty(line string) (icsProperty, error) {
+	// 따옴표 안의 ':'와 ';'는 구분자가 아닙니다.
+	inQuote := false
+	colon := -1
+	for i, ch := range line {
+		if ch == '"' {
+			inQuote = !inQuote
+		}
+		if ch == ':' && !inQuote {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return icsProperty{}, fmt.Errorf("잘못된 ICS 줄: %q", line)
+	}
+
+	prop := icsProperty{Params: make(map[string]string), Value: line[colon+1:]}
+	var parts []string
+	start := 0
+	inQuote = false
+	head := line[:colon]
+	for i, ch := range head {
+		if ch == '"' {
+			inQuote = !inQuote
+		}
+		if ch == ';' && !inQuote {
+			parts = append(parts, head[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, head[start:])
+
+	prop.Name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) == 2 {
+			prop.Params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+	return prop, nil
+}
+
+// parseICS는 ICS 문서를 컴포넌트 트리로 파싱합니다.
+func parseICS(r io.Reader) (*icsComponent, error) {
+	lines, err := unfoldICSLines(r)
+	if err != nil {
+		return nil, fmt.Errorf("ICS 읽기 실패: %v", err)
+	}
+	root := &icsComponent{Name: "ROOT"}
+	stack := []*icsComponent{root}
+	for _, line := range lines {
+		prop, err := parseICSProperty(line)
+		if err != nil {
+			// 깨진 줄 하나 때문에 전체 일정을 버리지 않습니다.
+			log.Printf("ICS 줄 무시: %v", err)
+			continue
+		}
+		current := stack[len(stack)-1]
+		switch prop.Name {
+		case "BEGIN":
+			child := &icsComponent{Name: strings.ToUpper(prop.Value)}
+			current.Components = append(current.Components, child)
+			stack = append(stack, child)
+		case "END":
+			if len(stack) == 1 || current.Name != strings.ToUpper(prop.Value) {
+				return nil, fmt.Errorf("ICS 구조 오류: END:%s", prop.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			current.Props = append(current.Props, prop)
+		}
+	}
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("ICS 구조 오류: %s가 닫히지 않았습니다", stack[len(stack)-1].Name)
+	}
+	return root, nil
+}
+
+func unescapeICSText(s string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return replacer.Replace(s)
+}
+
+// Outlook 등이 보내는 Windows 타임존 이름
+var windowsTimezones = map[string]string{
+	"Korea Standard Time": "Asia/Seoul",
+	"Tokyo Standard Time": "Asia/Tokyo",
+	"China Standard Time": "Asia/Shanghai",
+	"UTC":                 "UTC",
+}
+
+func icsLocation(tzid string) *time.Location {
+	if tzid == "" {
+		return seoul
+	}
+	if name, ok := windowsTimezones[tzid]; ok {
+		tzid = name
+	}
+	loc, err := time.LoadLocation(tzid)
+	if err != nil {
+		log.Printf("알 수 없는 TZID %q, 서울 시각으로 처리합니다", tzid)
+		return seoul
+	}
+	return loc
+}
+
+// parseICSTime은 DATE(20261017), UTC(…Z), TZID 지정, floating 시각을 처리합니다.
+// floating 시각과 종일 일정은 서울 기준으로 해석합니다.
+func parseICSTime(prop icsProperty) (t time.Time, allDay bool, err error) {
+	value := strings.TrimSpace(prop.Value)
+	if prop.Params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err = time.ParseInLocation("20060102", value, seoul)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	t, err = time.ParseInLocation("20060102T150405", value, icsLocation(prop.Params["TZID"]))
+	return t, false, err
+}
+
+// "P1D", "PT1H30M", "P1W" 같은 ICS 기간을 파싱합니다.
+func parseICSDuration(s string) (time.Duration, error) {
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
+	if !strings.HasPrefix(s, "P") {
+		return 0, fmt.Errorf("잘못된 DURATION: %q", s)
+	}
+	var total time.Duration
+	num := ""
+	inTime := false
+	for _, ch := range s[1:] {
+		switch {
+		case ch >= '0' && ch <= '9':
+			num += string(ch)
+		case ch == 'T':
+			inTime = true
+		default:
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return 0, fmt.Errorf("잘못된 DURATION: %q", s)
+			}
+			num = ""
+			switch {
+			case ch == 'W':
+				total += time.Duration(n) * 7 * 24 * time.Hour
+			case ch == 'D':
+				total += time.Duration(n) * 24 * time.Hour
+			case ch == 'H' && inTime:
+				total += time.Duration(n) * time.Hour
+			case ch == 'M' && inTime:
+				total += time.Duration(n) * time.Minute
+			case ch == 'S' && inTime:
+				total += time.Duration(n) * time.Second
+			default:
+				return 0, fmt.Errorf("잘못된 DURATION: %q", s)
+			}
+		}
+	}
+	if neg {
+		total = -total
+	}
+	return total, nil
+}
+
+// icsEvent는 VEVENT 하나를 해석한 결과입니다. 반복 규칙은 아직 펼치지 않은 상태입니다.
+type icsEvent struct {
+	UID          string
+	Summary      string
+	Location     string
+	Start        time.Time
+	Duration     time.Duration
+	AllDay       bool
+	RRule        map[string]string
+	ExDates      []time.Time
+	RecurrenceID time.Time // 반복 일정 중 한 회차만 수정한 경우
+	Cancelled    bool      // STATUS:CANCELLED
+	Lat, Lon     *float64
+}
+
+func parseICSEvent(c *icsComponent) (icsEvent, error) {
+	var ev icsEvent
+	startProp, ok := c.prop("DTSTART")
+	if !ok {
+		return ev, fmt.Errorf("DTSTART가 없는 일정")
+	}
+	var err error
+	if ev.Start, ev.AllDay, err = parseICSTime(startProp); err != nil {
+		return ev, fmt.Errorf("DTSTART 파싱 실패: %v", err)
+	}
+
+	// 종료 시각: DTEND > DURATION > (종일이면 하루, 아니면 0)
+	if ev.AllDay {
+		ev.Duration = 24 * time.Hour
+	}
+	if endProp, ok := c.prop("DTEND"); ok {
+		end, _, err := parseICSTime(endProp)
+		if err != nil {
+			return ev, fmt.Errorf("DTEND 파싱 실패: %v", err)
+		}
+		ev.Duration = end.Sub(ev.Start)
+	} else if durProp, ok := c.prop("DURATION"); ok {
+		if ev.Duration, err = parseICSDuration(durProp.Value); err != nil {
+			return ev, err
+		}
+	}
+
+	for _, p := range c.Props {
+		switch p.Name {
+		case "UID":
+			ev.UID = p.Value
+		case "SUMMARY":
+			ev.Summary = unescapeICSText(p.Value)
+		case "LOCATION":
+			ev.Location = unescapeICSText(p.Value)
+		case "RRULE":
+			ev.RRule = make(map[string]string)
+			for _, part := range strings.Split(p.Value, ";") {
+				kv := strings.SplitN(part, "=", 2)
+				if len(kv) == 2 {
+					ev.RRule[strings.ToUpper(kv[0])] = strings.ToUpper(kv[1])
+				}
+			}
+		case "EXDATE":
+			for _, v := range strings.Split(p.Value, ",") {
+				ex, _, err := parseICSTime(icsProperty{Params: p.Params, Value: v})
+				if err == nil {
+					ev.ExDates = append(ev.ExDates, ex)
+				}
+			}
+		case "RECURRENCE-ID":
+			if ev.RecurrenceID, _, err = parseICSTime(p); err != nil {
+				return ev, fmt.Errorf("RECURRENCE-ID 파싱 실패: %v", err)
+			}
+		case "GEO":
+			latLon := strings.SplitN(p.Value, ";", 2)
+			if len(latLon) == 2 {
+				lat, err1 := strconv.ParseFloat(latLon[0], 64)
+				lon, err2 := strconv.ParseFloat(latLon[1], 64)
+				if err1 == nil && err2 == nil {
+					ev.Lat, ev.Lon = &lat, &lon
+				}
+			}
+		}
+	}
+	return ev, nil
+}
+
+var icsWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// "2SA", "-1FR", "MO" 같은 BYDAY 값을 (순서, 요일)로 나눕니다. 순서가 없으면 0입니다.
+func parseByDay(s string) (int, time.Weekday, bool) {
+	if len(s) < 2 {
+		return 0, 0, false
+	}
+	day, ok := icsWeekdays[s[len(s)-2:]]
+	if !ok {
+		return 0, 0, false
+	}
+	n := 0
+	if prefix := s[:len(s)-2]; prefix != "" {
+		var err error
+		if n, err = strconv.Atoi(prefix); err != nil {
+			return 0, 0, false
+		}
+	}
+	return n, day, true
+}
+
+// 시작 시각의 시:분:초를 유지한 채 날짜만 바꿉니다.
+func atDate(start time.Time, year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+}
+
+// 한 달 안에서 BYDAY/BYMONTHDAY 조건에 맞는 날짜들
+func monthlyCandidates(ev icsEvent, year int, month time.Month) []time.Time {
+	first := time.Date(year, month, 1, 0, 0, 0, 0, ev.Start.Location())
+	daysInMonth := first.AddDate(0, 1, -1).Day()
+	var result []time.Time
+
+	if byMonthDay := ev.RRule["BYMONTHDAY"]; byMonthDay != "" {
+		for _, v := range strings.Split(byMonthDay, ",") {
+			d, err := strconv.Atoi(v)
+			if err != nil {
+				continue
+			}
+			if d < 0 {
+				d = daysInMonth + d + 1
+			}
+			if d >= 1 && d <= daysInMonth {
+				result = append(result, atDate(ev.Start, year, month, d))
+			}
+		}
+		return result
+	}
+	if byDay := ev.RRule["BYDAY"]; byDay != "" {
+		for _, v := range strings.Split(byDay, ",") {
+			n, weekday, ok := parseByDay(v)
+			if !ok {
+				continue
+			}
+			var matches []int
+			for d := 1; d <= daysInMonth; d++ {
+				if first.AddDate(0, 0, d-1).Weekday() == weekday {
+					matches = append(matches, d)
+				}
+			}
+			switch {
+			case n == 0:
+				for _, d := range matches {
+					result = append(result, atDate(ev.Start, year, month, d))
+				}
+			case n > 0 && n <= len(matches):
+				result = append(result, atDate(ev.Start, year, month, matches[n-1]))
+			case n < 0 && -n <= len(matches):
+				result = append(result, atDate(ev.Start, year, month, matches[len(matches)+n]))
+			}
+		}
+		return result
+	}
+	if ev.Start.Day() <= daysInMonth {
+		result = append(result, atDate(ev.Start, year, month, ev.Start.Day()))
+	}
+	return result
+}
+
+// occurrenceStarts는 반복 규칙을 펼쳐 until 이전의 모든 회차 시작 시각을 반환합니다.
+func occurrenceStarts(ev icsEvent, until time.Time) []time.Time {
+	if ev.RRule == nil {
+		return []time.Time{ev.Start}
+	}
+	interval := 1
+	if n, err := strconv.Atoi(ev.RRule["INTERVAL"]); err == nil && n > 0 {
+		interval = n
+	}
+	count := -1
+	if n, err := strconv.Atoi(ev.RRule["COUNT"]); err == nil {
+		count = n
+	}
+	if v := ev.RRule["UNTIL"]; v != "" {
+		// UNTIL은 UTC(…Z), 날짜, 또는 DTSTART와 같은 타임존의 시각입니다.
+		var t time.Time
+		var err error
+		switch {
+		case strings.HasSuffix(v, "Z"):
+			t, err = time.Parse("20060102T150405Z", v)
+		case len(v) == 8:
+			t, err = time.ParseInLocation("20060102", v, ev.Start.Location())
+			t = t.Add(24*time.Hour - time.Second)
+		default:
+			t, err = time.ParseInLocation("20060102T150405", v, ev.Start.Location())
+		}
+		if err == nil && t.Before(until) {
+			until = t.Add(time.Second)
+		}
+	}
+
+	var result []time.Time
+	add := func(candidates []time.Time) bool {
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+		for _, t := range candidates {
+			if t.Before(ev.Start) {
+				continue
+			}
+			if !t.Before(until) || count == 0 {
+				return false
+			}
+			result = append(result, t)
+			if count > 0 {
+				count--
+			}
+		}
+		return true
+	}
+
+	const maxPeriods = 5000
+	switch ev.RRule["FREQ"] {
+	case "DAILY":
+		for i := 0; i < maxPeriods; i++ {
+			if !add([]time.Time{ev.Start.AddDate(0, 0, i*interval)}) {
+				break
+			}
+		}
+	case "WEEKLY":
+		// 주의 시작은 월요일(WKST 기본값)
+		offset := (int(ev.Start.Weekday()) + 6) % 7
+		weekStart := ev.Start.AddDate(0, 0, -offset)
+		days := []time.Weekday{ev.Start.Weekday()}
+		if byDay := ev.RRule["BYDAY"]; byDay != "" {
+			days = nil
+			for _, v := range strings.Split(byDay, ",") {
+				if _, weekday, ok := parseByDay(v); ok {
+					days = append(days, weekday)
+				}
+			}
+		}
+		for i := 0; i < maxPeriods; i++ {
+			week := weekStart.AddDate(0, 0, i*7*interval)
+			var candidates []time.Time
+			for _, day := range days {
+				d := week.AddDate(0, 0, (int(day)+6)%7)
+				candidates = append(candidates, atDate(ev.Start, d.Year(), d.Month(), d.Day()))
+			}
+			if !add(candidates) {
+				break
+			}
+		}
+	case "MONTHLY":
+		for i := 0; i < maxPeriods; i++ {
+			m := time.Date(ev.Start.Year(), ev.Start.Month()+time.Month(i*interval), 1, 0, 0, 0, 0, ev.Start.Location())
+			if !add(monthlyCandidates(ev, m.Year(), m.Month())) {
+				break
+			}
+		}
+	case "YEARLY":
+		for i := 0; i < maxPeriods; i++ {
+			year := ev.Start.Year() + i*interval
+			// 2월 29일 일정은 윤년에만 돌아옵니다.
+			t := atDate(ev.Start, year, ev.Start.Month(), ev.Start.Day())
+			var candidates []time.Time
+			if t.Month() == ev.Start.Month() {
+				candidates = append(candidates, t)
+			}
+			if !add(candidates) {
+				break
+			}
+		}
+	default:
+		log.Printf("지원하지 않는 반복 규칙 FREQ=%s (%s)", ev.RRule["FREQ"], ev.Summary)
+		return []time.Time{ev.Start}
+	}
+	return result
+}
+
+// expandICSEvents는 캘린더의 모든 VEVENT를 [from, to)와 겹치는 회차로 펼칩니다.
+// 취소된 일정, EXDATE, RECURRENCE-ID로 대체된 회차를 반영합니다.
+func expandICSEvents(root *icsComponent, from, to time.Time) []models.CalendarEvent {
+	var events []icsEvent
+	for _, cal := range root.Components {
+		for _, c := range cal.Components {
+			if c.Name != "VEVENT" {
+				continue
+			}
+			ev, err := parseICSEvent(c)
+			if err != nil {
+				log.Printf("일정 무시: %v", err)
+				continue
+			}
+			// 취소된 회차도 원래 회차를 지워야 하므로 여기서 버리지 않고 아래에서 건너뜁니다.
+			if status, ok := c.prop("STATUS"); ok && strings.EqualFold(status.Value, "CANCELLED") {
+				ev.Cancelled = true
+			}
+			events = append(events, ev)
+		}
+	}
+
+	// 수정되거나 취소된 회차(RECURRENCE-ID)는 원래 반복 회차를 대신합니다.
+	overridden := make(map[string]bool)
+	for _, ev := range events {
+		if !ev.RecurrenceID.IsZero() {
+			overridden[ev.UID+"|"+ev.RecurrenceID.UTC().Format(time.RFC3339)] = true
+		}
+	}
+
+	var result []models.CalendarEvent
+	for _, ev := range events {
+		if ev.Cancelled {
+			continue
+		}
+		for _, start := range occurrenceStarts(ev, to) {
+			if ev.RecurrenceID.IsZero() && overridden[ev.UID+"|"+start.UTC().Format(time.RFC3339)] {
+				continue
+			}
+			excluded := false
+			for _, ex := range ev.ExDates {
+				excluded = excluded || ex.Equal(start)
+			}
+			end := start.Add(ev.Duration)
+			if excluded || !end.After(from) || !start.Before(to) {
+				continue
+			}
+			result = append(result, models.CalendarEvent{
+				UID:      ev.UID,
+				Summary:  ev.Summary,
+				Location: ev.Location,
+				Start:    start,
+				End:      end,
+				AllDay:   ev.AllDay,
+				Lat:      ev.Lat,
+				Lon:      ev.Lon,
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
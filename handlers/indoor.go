@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 실내 측정값은 이틀치만 보관합니다.
+const indoorRetention = 48 * time.Hour
+
+type indoorStore struct {
+	readings []models.IndoorReading
+	loaded   bool
+	mutex    sync.Mutex
+}
+
+var indoorReadings = &indoorStore{}
+
+func indoorReadingsPath() string { return dataPath("indoor_readings.json") }
+
+// 호출 시 s.mutex를 잡고 있어야 합니다.
+func (s *indoorStore) ensureLoaded() {
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+	if err := readJSONFile(indoorReadingsPath(), &s.readings); err != nil {
+		log.Printf("실내 측정값 불러오기 실패: %v", err)
+	}
+}
+
+func (s *indoorStore) add(reading models.IndoorReading) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ensureLoaded()
+
+	cutoff := reading.Time.Add(-indoorRetention)
+	kept := s.readings[:0]
+	for _, r := range s.readings {
+		if r.Time.After(cutoff) {
+			kept = append(kept, r)
+		}
+	}
+	s.readings = append(kept, reading)
+	sort.SliceStable(s.readings, func(i, j int) bool {
+		return s.readings[i].Time.Before(s.readings[j].Time)
+	})
+	return writeJSONFile(indoorReadingsPath(), s.readings)
+}
+
+// since 이후의 측정값을 시간순으로 반환합니다.
+func (s *indoorStore) since(since time.Time) []models.IndoorReading {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ensureLoaded()
+	var result []models.IndoorReading
+	for _, r := range s.readings {
+		if r.Time.After(since) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// 센서마다 가장 최근 측정값
+func (s *indoorStore) latest() []models.IndoorReading {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.ensureLoaded()
+	bySensor := make(map[string]models.IndoorReading)
+	for _, r := range s.readings {
+		bySensor[r.SensorID] = r
+	}
+	result := make([]models.IndoorReading, 0, len(bySensor))
+	for _, r := range bySensor {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].SensorID < result[j].SensorID
+	})
+	return result
+}
+
+// 센서가 보내는 JSON. time을 비우면 서버가 받은 시각을 씁니다.
+type indoorPayload struct {
+	Sensor      string    `json:"sensor"`
+	Temperature *float64  `json:"temperature"`
+	Humidity    *float64  `json:"humidity"`
+	Time        time.Time `json:"time"`
+}
+
+func parseIndoorPayload(body []byte, defaultSensor string) (models.IndoorReading, error) {
+	var payload indoorPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return models.IndoorReading{}, fmt.Errorf("잘못된 측정값 형식입니다")
+	}
+	if payload.Temperature == nil || payload.Humidity == nil {
+		return models.IndoorReading{}, fmt.Errorf("temperature와 humidity가 필요합니다")
+	}
+	if *payload.Temperature < -40 || *payload.Temperature > 85 {
+		return models.IndoorReading{}, fmt.Errorf("temperature가 측정 범위(-40~85℃)를 벗어났습니다")
+	}
+	if *payload.Humidity < 0 || *payload.Humidity > 100 {
+		return models.IndoorReading{}, fmt.Errorf("humidity는 0~100%%여야 합니다")
+	}
+	reading := models.IndoorReading{
+		SensorID:    strings.TrimSpace(payload.Sensor),
+		Temperature: *payload.Temperature,
+		Humidity:    *payload.Humidity,
+		Time:        payload.Time,
+	}
+	if reading.SensorID == "" {
+		reading.SensorID = defaultSensor
+	}
+	// 시계가 맞지 않는 센서가 많아 미래 시각이나 너무 오래된 시각은 받은 시각으로 바꿉니다.
+	now := time.Now()
+	if reading.Time.IsZero() || reading.Time.After(now.Add(5*time.Minute)) || now.Sub(reading.Time) > indoorRetention {
+		reading.Time = now
+	}
+	return reading, nil
+}
+
+// Authorization: Bearer <INDOOR_TOKEN> 또는 X-Sensor-Token 헤더로 인증합니다.
+// INDOOR_TOKEN이 설정되지 않았으면 수집 API는 꺼져 있습니다.
+func indoorAuthorized(r *http.Request) bool {
+	token := os.Getenv("INDOOR_TOKEN")
+	if token == "" {
+		return false
+	}
+	given := r.Header.Get("X-Sensor-Token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		given = strings.TrimPrefix(auth, "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+// PostIndoorReading은 실내 센서의 온습도 측정값을 받아 저장합니다.
+func PostIndoorReading(w http.ResponseWriter, r *http.Request) {
+	if !indoorAuthorized(r) {
+		http.Error(w, "인증에 실패했습니다.", http.StatusUnauthorized)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 4096))
+	if err != nil {
+		http.Error(w, "요청을 읽을 수 없습니다.", http.StatusBadRequest)
+		return
+	}
+	reading, err := parseIndoorPayload(body, "indoor")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := indoorReadings.add(reading); err != nil {
+		log.Printf("실내 측정값 저장 실패: %v", err)
+		http.Error(w, "측정값을 저장할 수 없습니다.", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// subscribeIndoorMQTT는 INDOOR_MQTT_TOPIC(예: home/indoor/+)을 구독해 측정값을 받습니다.
+// 센서 ID가 페이로드에 없으면 토픽의 마지막 단계를 씁니다.
+func subscribeIndoorMQTT() {
+	topic := os.Getenv("INDOOR_MQTT_TOPIC")
+	if mqttBroker == nil || topic == "" {
+		return
+	}
+	mqttBroker.Subscribe(topic, func(topic string, payload []byte) {
+		parts := strings.Split(topic, "/")
+		reading, err := parseIndoorPayload(payload, parts[len(parts)-1])
+		if err != nil {
+			log.Printf("MQTT 실내 측정값 무시 (%s): %v", topic, err)
+			return
+		}
+		if err := indoorReadings.add(reading); err != nil {
+			log.Printf("실내 측정값 저장 실패: %v", err)
+		}
+	})
+	log.Printf("실내 센서 MQTT 구독: %s", topic)
+}
+
+// 절대 습도 (g/m³). 같은 상대 습도라도 기온에 따라 공기 중 수증기 양이 다르므로 이것으로 비교합니다.
+func absoluteHumidity(temp, rh float64) float64 {
+	saturation := 6.112 * math.Exp(17.67*temp/(temp+243.5))
+	return saturation * rh * 2.1674 / (273.15 + temp)
+}
+
+// ventilationAdvice는 바깥이 실내보다 나은지 보고 환기를 권할지 정합니다.
+// 비/눈이 오거나 한파·폭염이면 권하지 않습니다.
+func ventilationAdvice(indoor models.IndoorReading, outdoor models.WeatherItem) (bool, string) {
+	outTemp, okTemp := numericValue(outdoor.Tmp)
+	outHumid, okHumid := numericValue(outdoor.Humidity)
+	if !okTemp || !okHumid {
+		return false, "바깥 예보가 없어 비교할 수 없어요."
+	}
+	if outdoor.Pty != "none" {
+		return false, fmt.Sprintf("밖에 %s 소식이 있어 창문은 닫아 두세요.", precipWord(outdoor.Pty))
+	}
+	if outTemp <= 0 || outTemp >= 33 {
+		return false, fmt.Sprintf("바깥 기온이 %.0f℃라 환기는 짧게만 하세요.", outTemp)
+	}
+
+	inAbs := absoluteHumidity(indoor.Temperature, indoor.Humidity)
+	outAbs := absoluteHumidity(outTemp, outHumid)
+	switch {
+	case indoor.Humidity >= 60 && outAbs < inAbs-1:
+		return true, fmt.Sprintf("실내가 습해요(%.0f%%). 바깥 공기가 더 건조하니 환기하세요.", indoor.Humidity)
+	case indoor.Temperature >= 26 && outTemp <= indoor.Temperature-2 && outTemp >= 18:
+		return true, fmt.Sprintf("바깥이 %.0f℃로 더 시원해요. 창문을 열어 열기를 빼세요.", outTemp)
+	case indoor.Temperature <= 19 && outTemp >= indoor.Temperature+2 && outTemp <= 26:
+		return true, fmt.Sprintf("바깥이 %.0f℃로 더 따뜻해요. 잠깐 창문을 열어 보세요.", outTemp)
+	case indoor.Humidity <= 30 && outAbs > inAbs+1:
+		return true, "실내가 건조해요. 바깥 공기가 더 촉촉하니 환기하세요."
+	}
+	return false, "지금은 실내 공기가 바깥보다 나빠 보이지 않아요."
+}
+
+// 지금 칸 예보가 없을 때 바깥 날씨로 쓸 관측/예보를 찾는 범위
+const outdoorFallbackWindow = 3 * time.Hour
+
+// currentOutdoor는 지금 바깥 날씨입니다.
+// 동네예보는 발표 직후 다음 시각부터 시작해 지금 칸이 비므로, 그때는 최근 초단기실황 관측을 쓰고
+// 관측도 없으면 가장 가까운 다음 예보 칸을 씁니다.
+func currentOutdoor(allWeather []models.WeatherItem, now time.Time) *models.WeatherItem {
+	if slot := slotAt(allWeather, now); slot != nil {
+		return slot
+	}
+
+	records, err := observationsBetween(locationKey(homeLocation()), now.Add(-outdoorFallbackWindow), now)
+	if err != nil {
+		log.Printf("관측 이력 읽기 실패: %v", err)
+	}
+	if n := len(records); n > 0 {
+		latest := records[n-1]
+		tmp, okTmp := latest.Values["T1H"]
+		humidity, okHumidity := latest.Values["REH"]
+		if okTmp && okHumidity {
+			pty := "none"
+			if v, ok := latest.Values["PTY"]; ok {
+				pty = parseCategory("PTY", v)
+			}
+			return &models.WeatherItem{
+				Date:     latest.Time.Format("20060102"),
+				Time:     latest.Time.Format("1504"),
+				Tmp:      tmp + "℃",
+				Humidity: humidity + "%",
+				Pty:      pty,
+			}
+		}
+	}
+
+	if upcoming := slotsBetween(allWeather, now, now.Add(outdoorFallbackWindow)); len(upcoming) > 0 {
+		return &upcoming[0]
+	}
+	return nil
+}
+
+// indoorComparisons는 센서마다 최신 측정값과 현재 시각의 바깥 예보를 비교합니다.
+func indoorComparisons() ([]models.IndoorComparison, error) {
+	latest := indoorReadings.latest()
+	if len(latest) == 0 {
+		return nil, nil
+	}
+	allWeather, err := fetchAndCacheWeather()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().In(seoul)
+	outdoor := currentOutdoor(allWeather, now)
+
+	var result []models.IndoorComparison
+	for _, reading := range latest {
+		comparison := models.IndoorComparison{Indoor: reading}
+		if outdoor != nil {
+			if v, ok := numericValue(outdoor.Tmp); ok {
+				comparison.OutdoorTemp = &v
+			}
+			if v, ok := numericValue(outdoor.Humidity); ok {
+				comparison.OutdoorHumid = &v
+			}
+			comparison.Ventilate, comparison.Recommendation = ventilationAdvice(reading, *outdoor)
+		}
+		result = append(result, comparison)
+	}
+	return result, nil
+}
+
+// GetIndoorReadings는 최근 실내 측정값을 JSON으로 반환합니다. (?hours=24, 최대 48)
+func GetIndoorReadings(w http.ResponseWriter, r *http.Request) {
+	hours := 24
+	if v, err := strconv.Atoi(r.URL.Query().Get("hours")); err == nil && v > 0 && v <= 48 {
+		hours = v
+	}
+	readings := indoorReadings.since(time.Now().Add(-time.Duration(hours) * time.Hour))
+	if readings == nil {
+		readings = []models.IndoorReading{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(readings)
+}
+
+// GetIndoorComparison은 실내와 바깥 온습도를 나란히 보여주고 환기 여부를 알려줍니다.
+func GetIndoorComparison(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	comparisons, err := indoorComparisons()
+	if err != nil {
+		http.Error(w, "날씨 정보를 가져올 수 없습니다.", http.StatusInternalServerError)
+		return
+	}
+	if len(comparisons) == 0 {
+		// 센서가 없으면 패널을 비워 둡니다.
+		return
+	}
+
+	formatValue := func(v *float64, unit string) string {
+		if v == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%.0f%s", *v, unit)
+	}
+	type comparisonView struct {
+		Sensor, Stale                                  string
+		IndoorTempClass, IndoorTemp, IndoorHumidity    string
+		OutdoorTempClass, OutdoorTemp, OutdoorHumidity string
+		Ventilate                                      bool
+		Recommendation                                 string
+	}
+	var views []comparisonView
+	for _, c := range comparisons {
+		view := comparisonView{
+			Sensor:          c.Indoor.SensorID,
+			IndoorTempClass: getTempClass(fmt.Sprintf("%.0f℃", c.Indoor.Temperature)),
+			IndoorTemp:      fmt.Sprintf("%.1f℃", c.Indoor.Temperature),
+			IndoorHumidity:  fmt.Sprintf("%.0f%%", c.Indoor.Humidity),
+			OutdoorTemp:     formatValue(c.OutdoorTemp, "℃"),
+			OutdoorHumidity: formatValue(c.OutdoorHumid, "%"),
+			Ventilate:       c.Ventilate,
+			Recommendation:  c.Recommendation,
+		}
+		view.OutdoorTempClass = getTempClass(view.OutdoorTemp)
+		if age := time.Since(c.Indoor.Time); age > 30*time.Minute {
+			view.Stale = fmt.Sprintf("%.0f분", age.Minutes())
+		}
+		views = append(views, view)
+	}
+	renderFragment(w, "indoor-comparison", views)
+}
@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 한국 음력은 한국 표준시(동경 135°) 기준으로 합삭과 중기를 따져 정합니다.
+// - 합삭(신월)이 든 날이 그 달의 1일입니다.
+// - 동지가 든 달이 11월입니다.
+// - 동지와 다음 동지 사이에 달이 13개 있으면, 그중 중기(태양 황경이 30°의 배수)가 없는 첫 달이 윤달입니다.
+// 합삭은 Meeus 천문 알고리즘 49장, 태양 황경은 25장의 식을 씁니다.
+
+const (
+	lunarTimeZone = 9.0 // 한국 표준시 (시간)
+	// 지구 자전이 느려져 생기는 역학시와 세계시의 차이 (2020년대 약 69초)
+	deltaTSeconds = 69.0
+)
+
+// 율리우스 적일 (그날 정오 기준 정수)
+func julianDayNumber(y int, m time.Month, d int) int {
+	a := (14 - int(m)) / 12
+	yy := y + 4800 - a
+	mm := int(m) + 12*a - 3
+	return d + (153*mm+2)/5 + 365*yy + yy/4 - yy/100 + yy/400 - 32045
+}
+
+func dateFromJulianDayNumber(jdn int) time.Time {
+	a := jdn + 32044
+	b := (4*a + 3) / 146097
+	c := a - b*146097/4
+	d := (4*c + 3) / 1461
+	e := c - 1461*d/4
+	m := (5*e + 2) / 153
+	day := e - (153*m+2)/5 + 1
+	month := m + 3 - 12*(m/10)
+	year := b*100 + d - 4800 + m/10
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, seoul)
+}
+
+func degSin(x float64) float64 { return math.Sin(x * math.Pi / 180) }
+
+// newMoonJDE는 k번째 합삭(k=0은 2000년 1월 6일)의 역학시 율리우스일입니다.
+func newMoonJDE(k float64) float64 {
+	t := k / 1236.85
+	t2, t3, t4 := t*t, t*t*t, t*t*t*t
+	jde := 2451550.09766 + 29.530588861*k + 0.00015437*t2 - 0.000000150*t3 + 0.00000000073*t4
+	e := 1 - 0.002516*t - 0.0000074*t2
+	m := 2.5534 + 29.10535670*k - 0.0000014*t2 - 0.00000011*t3
+	mp := 201.5643 + 385.81693528*k + 0.0107582*t2 + 0.00001238*t3 - 0.000000058*t4
+	f := 160.7108 + 390.67050284*k - 0.0016118*t2 - 0.00000227*t3 + 0.000000011*t4
+	omega := 124.7746 - 1.56375588*k + 0.0020672*t2 + 0.00000215*t3
+
+	jde += -0.40720*degSin(mp) +
+		0.17241*e*degSin(m) +
+		0.01608*degSin(2*mp) +
+		0.01039*degSin(2*f) +
+		0.00739*e*degSin(mp-m) -
+		0.00514*e*degSin(mp+m) +
+		0.00208*e*e*degSin(2*m) -
+		0.00111*degSin(mp-2*f) -
+		0.00057*degSin(mp+2*f) +
+		0.00056*e*degSin(2*mp+m) -
+		0.00042*degSin(3*mp) +
+		0.00042*e*degSin(m+2*f) +
+		0.00038*e*degSin(m-2*f) -
+		0.00024*e*degSin(2*mp-m) -
+		0.00017*degSin(omega) -
+		0.00007*degSin(mp+2*m) +
+		0.00004*degSin(2*mp-2*f) +
+		0.00004*degSin(3*m) +
+		0.00003*degSin(mp+m-2*f) +
+		0.00003*degSin(2*mp+2*f) -
+		0.00003*degSin(mp+m+2*f) +
+		0.00003*degSin(mp-m+2*f) -
+		0.00002*degSin(mp-m-2*f) -
+		0.00002*degSin(3*mp+m) +
+		0.00002*degSin(4*mp)
+
+	// 행성에 의한 보정
+	planetary := []struct{ coef, base, rate float64 }{
+		{0.000325, 299.77, 0.107408}, {0.000165, 251.88, 0.016321}, {0.000164, 251.83, 26.651886},
+		{0.000126, 349.42, 36.412478}, {0.000110, 84.66, 18.206239}, {0.000062, 141.74, 53.303771},
+		{0.000060, 207.14, 2.453732}, {0.000056, 154.84, 7.306860}, {0.000047, 34.52, 27.261239},
+		{0.000042, 207.19, 0.121824}, {0.000040, 291.34, 1.844379}, {0.000037, 161.72, 24.198154},
+		{0.000035, 239.56, 25.513099}, {0.000023, 331.55, 3.592518},
+	}
+	for i, p := range planetary {
+		arg := p.base + p.rate*k
+		if i == 0 {
+			arg -= 0.009173 * t2
+		}
+		jde += p.coef * degSin(arg)
+	}
+	return jde
+}
+
+// newMoonDay는 k번째 합삭이 든 날(한국 표준시)의 율리우스 적일입니다.
+func newMoonDay(k int) int {
+	jd := newMoonJDE(float64(k)) - deltaTSeconds/86400
+	return int(math.Floor(jd + 0.5 + lunarTimeZone/24))
+}
+
+// sunLongitude는 역학시 율리우스일 jde의 태양 겉보기 황경(도, 0~360)입니다.
+func sunLongitude(jde float64) float64 {
+	t := (jde - 2451545.0) / 36525
+	l0 := 280.46646 + 36000.76983*t + 0.0003032*t*t
+	m := 357.52911 + 35999.05029*t - 0.0001537*t*t
+	c := (1.914602-0.004817*t-0.000014*t*t)*degSin(m) +
+		(0.019993-0.000101*t)*degSin(2*m) +
+		0.000289*degSin(3*m)
+	omega := 125.04 - 1934.136*t
+	lambda := l0 + c - 0.00569 - 0.00478*degSin(omega)
+	return math.Mod(math.Mod(lambda, 360)+360, 360)
+}
+
+// 율리우스 적일 jdn인 날 0시(한국 표준시)의 태양 황경
+func sunLongitudeAtDayStart(jdn int) float64 {
+	return sunLongitude(float64(jdn) - 0.5 - lunarTimeZone/24 + deltaTSeconds/86400)
+}
+
+// 그날 0시의 태양 황경이 몇 번째 중기 구간(30°씩, 0~11)에 있는지
+func majorTermSector(jdn int) int {
+	return int(sunLongitudeAtDayStart(jdn) / 30)
+}
+
+// jdn이 든 달을 시작한 합삭의 번호
+func newMoonIndexOnOrBefore(jdn int) int {
+	k := int(math.Floor((float64(jdn) - 2451550.09766) / 29.530588853))
+	for newMoonDay(k+1) <= jdn {
+		k++
+	}
+	for newMoonDay(k) > jdn {
+		k--
+	}
+	return k
+}
+
+// lunarMonth11은 year년 동지가 든 음력 11월의 첫날입니다.
+func lunarMonth11(year int) int {
+	k := newMoonIndexOnOrBefore(julianDayNumber(year, time.December, 31))
+	start := newMoonDay(k)
+	// 그 달 첫날 0시에 이미 동지(270°)가 지났다면 동지는 앞 달에 들어 있습니다.
+	if majorTermSector(start) >= 9 {
+		start = newMoonDay(k - 1)
+	}
+	return start
+}
+
+// leapMonthOffset은 11월(a11)부터 몇 번째 달이 윤달인지 찾습니다. 중기가 없는 첫 달입니다.
+func leapMonthOffset(a11 int) int {
+	k := newMoonIndexOnOrBefore(a11)
+	last := majorTermSector(newMoonDay(k + 1))
+	i := 1
+	for i < 14 {
+		i++
+		sector := majorTermSector(newMoonDay(k + i))
+		if sector == last {
+			break
+		}
+		last = sector
+	}
+	return i - 1
+}
+
+// 같은 해를 여러 번 계산하지 않도록 11월 첫날을 기억해 둡니다.
+var month11Cache = struct {
+	days  map[int]int
+	mutex sync.Mutex
+}{days: make(map[int]int)}
+
+func cachedLunarMonth11(year int) int {
+	month11Cache.mutex.Lock()
+	defer month11Cache.mutex.Unlock()
+	if day, ok := month11Cache.days[year]; ok {
+		return day
+	}
+	day := lunarMonth11(year)
+	month11Cache.days[year] = day
+	return day
+}
+
+// solarToLunar는 양력 날짜를 음력으로 바꿉니다.
+func solarToLunar(day time.Time) models.LunarDate {
+	jdn := julianDayNumber(day.Year(), day.Month(), day.Day())
+	monthStart := newMoonDay(newMoonIndexOnOrBefore(jdn))
+
+	year := day.Year()
+	a11 := cachedLunarMonth11(year)
+	b11 := a11
+	lunarYear := year
+	if a11 >= monthStart {
+		a11 = cachedLunarMonth11(year - 1)
+	} else {
+		lunarYear = year + 1
+		b11 = cachedLunarMonth11(year + 1)
+	}
+
+	diff := int(math.Floor(float64(monthStart-a11)/29 + 1e-9))
+	month := diff + 11
+	leap := false
+	if b11-a11 > 365 {
+		leapOffset := leapMonthOffset(a11)
+		if diff >= leapOffset {
+			month = diff + 10
+			leap = diff == leapOffset
+		}
+	}
+	if month > 12 {
+		month -= 12
+	}
+	if month >= 11 && diff < 4 {
+		lunarYear--
+	}
+	return models.LunarDate{Year: lunarYear, Month: month, Day: jdn - monthStart + 1, Leap: leap}
+}
+
+// lunarToSolar는 음력 날짜를 양력으로 바꿉니다. 그해에 없는 윤달이면 false입니다.
+func lunarToSolar(year, month, day int, leap bool) (time.Time, bool) {
+	var a11, b11 int
+	if month < 11 {
+		a11, b11 = cachedLunarMonth11(year-1), cachedLunarMonth11(year)
+	} else {
+		a11, b11 = cachedLunarMonth11(year), cachedLunarMonth11(year+1)
+	}
+	k := newMoonIndexOnOrBefore(a11)
+	offset := month - 11
+	if offset < 0 {
+		offset += 12
+	}
+	if b11-a11 > 365 {
+		leapOffset := leapMonthOffset(a11)
+		leapMonth := leapOffset - 2
+		if leapMonth < 0 {
+			leapMonth += 12
+		}
+		if leap && month != leapMonth {
+			return time.Time{}, false
+		}
+		if leap || offset >= leapOffset {
+			offset++
+		}
+	} else if leap {
+		return time.Time{}, false
+	}
+	return dateFromJulianDayNumber(newMoonDay(k+offset) + day - 1), true
+}
+
+// 24절기. 태양 황경 0°(춘분)부터 15°씩입니다.
+var solarTermNames = []string{
+	"춘분", "청명", "곡우", "입하", "소만", "망종", "하지", "소서", "대서", "입추", "처서", "백로",
+	"추분", "한로", "상강", "입동", "소설", "대설", "동지", "소한", "대한", "입춘", "우수", "경칩",
+}
+
+// solarTermOn은 그날(한국 표준시 0~24시) 시작하는 절기와 그 시각입니다.
+func solarTermOn(day time.Time) (string, time.Time, bool) {
+	jdn := julianDayNumber(day.Year(), day.Month(), day.Day())
+	start, end := sunLongitudeAtDayStart(jdn), sunLongitudeAtDayStart(jdn+1)
+	if end < start {
+		end += 360
+	}
+	if math.Floor(start/15) == math.Floor(end/15) {
+		return "", time.Time{}, false
+	}
+	target := math.Floor(end/15) * 15
+
+	// 하루 안에서 이분법으로 황경이 target을 지나는 시각을 찾습니다.
+	lo := float64(jdn) - 0.5 - lunarTimeZone/24
+	hi := lo + 1
+	for i := 0; i < 30; i++ {
+		mid := (lo + hi) / 2
+		lon := sunLongitude(mid + deltaTSeconds/86400)
+		if lon < start-180 {
+			lon += 360
+		}
+		if lon >= target {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	at := time.Unix(0, int64((hi-2440587.5)*86400*float64(time.Second))).In(seoul)
+	return solarTermNames[int(math.Mod(target, 360)/15)], at, true
+}
+
+// currentSolarTerm은 그날 기준으로 가장 최근에 시작한 절기입니다.
+func currentSolarTerm(day time.Time) (string, time.Time) {
+	for i := 0; i < 20; i++ {
+		if name, at, ok := solarTermOn(day.AddDate(0, 0, -i)); ok {
+			return name, at
+		}
+	}
+	return "", time.Time{}
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 달 위치와 위상은 저정밀 천문 계산식(Astronomy Answers / suncalc 방식)으로 구합니다.
+// 월출/월몰 오차는 몇 분 정도로, 화면에 보여주기에는 충분합니다.
+
+const (
+	astroRad       = math.Pi / 180
+	earthObliquity = 23.4397 * astroRad
+	synodicMonth   = 29.530588853
+	// 달이 지평선에 걸린 것으로 보는 고도 (시차와 굴절 감안)
+	moonHorizonAlt = 0.133 * astroRad
+)
+
+// J2000(2000-01-01 12:00 UTC) 기준 경과 일수
+func daysSinceJ2000(t time.Time) float64 {
+	return float64(t.UnixNano())/float64(24*time.Hour) + 2440587.5 - 2451545.0
+}
+
+func rightAscension(l, b float64) float64 {
+	return math.Atan2(math.Sin(l)*math.Cos(earthObliquity)-math.Tan(b)*math.Sin(earthObliquity), math.Cos(l))
+}
+
+func declinationOf(l, b float64) float64 {
+	return math.Asin(math.Sin(b)*math.Cos(earthObliquity) + math.Cos(b)*math.Sin(earthObliquity)*math.Sin(l))
+}
+
+type celestialCoords struct {
+	ra, dec, dist float64 // 적경, 적위 (rad), 거리 (km)
+}
+
+func sunCoords(d float64) celestialCoords {
+	m := astroRad * (357.5291 + 0.98560028*d)
+	c := astroRad * (1.9148*math.Sin(m) + 0.02*math.Sin(2*m) + 0.0003*math.Sin(3*m))
+	l := m + c + astroRad*102.9372 + math.Pi
+	return celestialCoords{ra: rightAscension(l, 0), dec: declinationOf(l, 0), dist: 149598000}
+}
+
+func moonCoords(d float64) celestialCoords {
+	l := astroRad * (218.316 + 13.176396*d) // 평균 황경
+	m := astroRad * (134.963 + 13.064993*d) // 평균 근점 이각
+	f := astroRad * (93.272 + 13.229350*d)  // 평균 승교점 이각
+
+	lon := l + astroRad*6.289*math.Sin(m)
+	lat := astroRad * 5.128 * math.Sin(f)
+	return celestialCoords{ra: rightAscension(lon, lat), dec: declinationOf(lon, lat), dist: 385001 - 20905*math.Cos(m)}
+}
+
+// moonAltitude는 t 시각, 위경도에서 본 달의 고도(rad, 대기 굴절 포함)입니다.
+func moonAltitude(t time.Time, lat, lon float64) float64 {
+	d := daysSinceJ2000(t)
+	c := moonCoords(d)
+	phi := astroRad * lat
+	siderealTime := astroRad*(280.16+360.9856235*d) + astroRad*lon
+	h := siderealTime - c.ra
+	alt := math.Asin(math.Sin(phi)*math.Sin(c.dec) + math.Cos(phi)*math.Cos(c.dec)*math.Cos(h))
+	return alt + refraction(alt)
+}
+
+// 대기 굴절 보정 (지평선 아래는 지평선 값을 씁니다)
+func refraction(alt float64) float64 {
+	if alt < 0 {
+		alt = 0
+	}
+	return 0.0002967 / math.Tan(alt+0.00312536/(alt+0.08901179))
+}
+
+// moonIllumination은 t 시각의 위상(0~1)과 밝은 면의 비율(0~1)입니다.
+func moonIllumination(t time.Time) (phase, fraction float64) {
+	d := daysSinceJ2000(t)
+	s, m := sunCoords(d), moonCoords(d)
+	elongation := math.Acos(math.Sin(s.dec)*math.Sin(m.dec) + math.Cos(s.dec)*math.Cos(m.dec)*math.Cos(s.ra-m.ra))
+	inc := math.Atan2(s.dist*math.Sin(elongation), m.dist-s.dist*math.Cos(elongation))
+	angle := math.Atan2(math.Cos(s.dec)*math.Sin(s.ra-m.ra),
+		math.Sin(s.dec)*math.Cos(m.dec)-math.Cos(s.dec)*math.Sin(m.dec)*math.Cos(s.ra-m.ra))
+	sign := 1.0
+	if angle < 0 {
+		sign = -1
+	}
+	return 0.5 + 0.5*inc*sign/math.Pi, (1 + math.Cos(inc)) / 2
+}
+
+var moonPhases = []struct {
+	Name  string
+	Emoji string
+}{
+	{"삭", "🌑"},
+	{"초승달", "🌒"},
+	{"상현", "🌓"},
+	{"상현망간", "🌔"},
+	{"망", "🌕"},
+	{"하현망간", "🌖"},
+	{"하현", "🌗"},
+	{"그믐달", "🌘"},
+}
+
+// moonPhaseAt은 위상을 8단계로 나눈 이름과 아이콘입니다.
+func moonPhaseAt(t time.Time) (name, emoji string) {
+	phase, _ := moonIllumination(t)
+	p := moonPhases[int(math.Floor(phase*8+0.5))%8]
+	return p.Name, p.Emoji
+}
+
+// moonTimes는 그날(서울 시각 0~24시) 월출/월몰 시각을 찾습니다.
+// 한 시간 간격으로 고도를 구하고 두 시간 구간마다 2차 곡선으로 지평선을 지나는 시각을 보간합니다.
+func moonTimes(day time.Time, lat, lon float64) (rise, set *time.Time, alwaysUp, alwaysDown bool) {
+	y, m, d := day.Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, seoul)
+	at := func(hours float64) time.Time {
+		return start.Add(time.Duration(hours * float64(time.Hour)))
+	}
+	var riseHour, setHour *float64
+	h0 := moonAltitude(start, lat, lon) - moonHorizonAlt
+	var ye float64
+	for i := 1.0; i <= 24; i += 2 {
+		h1 := moonAltitude(at(i), lat, lon) - moonHorizonAlt
+		h2 := moonAltitude(at(i+1), lat, lon) - moonHorizonAlt
+
+		a := (h0+h2)/2 - h1
+		b := (h2 - h0) / 2
+		xe := -b / (2 * a)
+		ye = (a*xe+b)*xe + h1
+		disc := b*b - 4*a*h1
+		roots := 0
+		var x1, x2 float64
+		if disc >= 0 {
+			dx := math.Sqrt(disc) / (math.Abs(a) * 2)
+			x1, x2 = xe-dx, xe+dx
+			if math.Abs(x1) <= 1 {
+				roots++
+			}
+			if math.Abs(x2) <= 1 {
+				roots++
+			}
+			if x1 < -1 {
+				x1 = x2
+			}
+		}
+
+		switch roots {
+		case 1:
+			hour := i + x1
+			if h0 < 0 {
+				riseHour = &hour
+			} else {
+				setHour = &hour
+			}
+		case 2:
+			r, s := i+x1, i+x2
+			if ye < 0 {
+				r, s = i+x2, i+x1
+			}
+			riseHour, setHour = &r, &s
+		}
+		if riseHour != nil && setHour != nil {
+			break
+		}
+		h0 = h2
+	}
+
+	if riseHour != nil {
+		t := at(*riseHour)
+		rise = &t
+	}
+	if setHour != nil {
+		t := at(*setHour)
+		set = &t
+	}
+	if rise == nil && set == nil {
+		alwaysUp, alwaysDown = ye > 0, ye <= 0
+	}
+	return rise, set, alwaysUp, alwaysDown
+}
+
+// moonInfo는 날짜의 달 정보를 모읍니다. 위상은 그날 21시(저녁에 달을 보는 시각) 기준입니다.
+func moonInfo(day time.Time, lat, lon float64) models.MoonInfo {
+	y, m, d := day.Date()
+	evening := time.Date(y, m, d, 21, 0, 0, 0, seoul)
+	phase, fraction := moonIllumination(evening)
+	name, emoji := moonPhaseAt(evening)
+	info := models.MoonInfo{
+		Date:         evening.Format("20060102"),
+		Phase:        math.Round(phase*1000) / 1000,
+		PhaseName:    name,
+		Emoji:        emoji,
+		Illumination: math.Round(fraction * 100),
+		Age:          math.Round(phase*synodicMonth*10) / 10,
+	}
+	info.Moonrise, info.Moonset, info.AlwaysUp, info.AlwaysDown = moonTimes(day, lat, lon)
+	return info
+}
+
+func clockOrDash(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format("15:04")
+}
+
+// GetAstronomyJSON은 집 위치의 해와 달 정보를 함께 JSON으로 반환합니다. (?date=20060102)
+func GetAstronomyJSON(w http.ResponseWriter, r *http.Request) {
+	day, err := astronomyDay(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	lat, lon := homeCoordinates()
+	sun, err := sunInfo(day, lat, lon, time.Now().In(seoul))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.AstronomyInfo{Sun: sun, Moon: moonInfo(day, lat, lon)})
+}
+
+// GetMoonInfo는 머리글에 넣을 달 위상과 월출/월몰 조각을 반환합니다.
+func GetMoonInfo(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	day, err := astronomyDay(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	lat, lon := homeCoordinates()
+	info := moonInfo(day, lat, lon)
+	renderFragment(w, "moon-info", struct {
+		Age, Emoji, PhaseName, Illumination, Moonrise, Moonset string
+	}{
+		Age:          fmt.Sprintf("%.1f", info.Age),
+		Emoji:        info.Emoji,
+		PhaseName:    info.PhaseName,
+		Illumination: fmt.Sprintf("%.0f%%", info.Illumination),
+		Moonrise:     clockOrDash(info.Moonrise),
+		Moonset:      clockOrDash(info.Moonset),
+	})
+}
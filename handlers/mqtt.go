@@ -0,0 +1,601 @@
+package handlers
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MQTT 3.1.1 패킷 종류 (고정 헤더 상위 4비트)
+const (
+	mqttConnect   = 1
+	mqttConnack   = 2
+	mqttPublish   = 3
+	mqttPuback    = 4
+	mqttPubrec    = 5
+	mqttPubrel    = 6
+	mqttPubcomp   = 7
+	mqttSubscribe = 8
+	mqttSuback    = 9
+	mqttPingreq   = 12
+	mqttPingresp  = 13
+)
+
+const (
+	mqttAckTimeout  = 10 * time.Second
+	mqttDialTimeout = 10 * time.Second
+	// 받을 수 있는 패킷 본문 최대 크기. 프로토콜 상한(256MB)을 그대로 믿고 메모리를 잡지 않도록 합니다.
+	mqttMaxPacketSize = 256 * 1024
+	// 연결이 이만큼 유지된 뒤에 끊겨야 재연결 대기 시간을 처음으로 되돌립니다.
+	mqttStableConnection = time.Minute
+)
+
+var errMQTTNotConnected = errors.New("MQTT 브로커에 연결되어 있지 않습니다")
+
+// CONNACK 반환 코드 (MQTT 3.1.1 3.2.2.3)
+var mqttConnackErrors = map[byte]string{
+	1: "지원하지 않는 프로토콜 버전",
+	2: "거부된 클라이언트 ID",
+	3: "브로커를 사용할 수 없음",
+	4: "잘못된 사용자 이름 또는 비밀번호",
+	5: "권한 없음",
+}
+
+type mqttConfig struct {
+	Broker       string // tcp://host:1883, mqtts://host:8883 (스킴이 없으면 tcp)
+	ClientID     string
+	Username     string
+	Password     string
+	KeepAlive    time.Duration
+	QoS          byte          // 발행/구독에 쓰는 QoS (0, 1, 2)
+	ReconnectMin time.Duration // 첫 재연결 대기 시간 (0이면 1초)
+	ReconnectMax time.Duration
+	WillTopic    string // 연결이 끊기면 브로커가 WillPayload를 retain으로 발행합니다.
+	WillPayload  string
+}
+
+// mqttConfigFromEnv는 MQTT_* 환경변수로 설정을 만듭니다. MQTT_BROKER가 없으면 false입니다.
+func mqttConfigFromEnv() (mqttConfig, bool) {
+	config := mqttConfig{
+		Broker:       os.Getenv("MQTT_BROKER"),
+		ClientID:     os.Getenv("MQTT_CLIENT_ID"),
+		Username:     os.Getenv("MQTT_USERNAME"),
+		Password:     os.Getenv("MQTT_PASSWORD"),
+		KeepAlive:    60 * time.Second,
+		QoS:          1,
+		ReconnectMin: time.Second,
+		ReconnectMax: time.Minute,
+	}
+	if config.Broker == "" {
+		return config, false
+	}
+	if config.ClientID == "" {
+		config.ClientID = "weather-reminder"
+	}
+	if v, err := strconv.Atoi(os.Getenv("MQTT_QOS")); err == nil && v >= 0 && v <= 2 {
+		config.QoS = byte(v)
+	}
+	if v, err := strconv.Atoi(os.Getenv("MQTT_KEEPALIVE")); err == nil && v > 0 {
+		config.KeepAlive = time.Duration(v) * time.Second
+	}
+	if v, err := strconv.Atoi(os.Getenv("MQTT_RECONNECT_MAX")); err == nil && v > 0 {
+		config.ReconnectMax = time.Duration(v) * time.Second
+	}
+	return config, true
+}
+
+type mqttMessageHandler func(topic string, payload []byte)
+
+// mqttClient는 표준 라이브러리만으로 만든 MQTT 3.1.1 클라이언트입니다.
+// 연결이 끊기면 지수 백오프로 다시 연결하고, 연결될 때마다 구독을 복구한 뒤 OnConnect를 호출합니다.
+type mqttClient struct {
+	config    mqttConfig
+	OnConnect func()
+
+	mutex     sync.Mutex
+	conn      net.Conn
+	connected bool
+	closed    chan struct{} // Close가 닫으면 Run이 끝납니다
+	lastError string
+	nextID    uint16
+	inflight  map[uint16]chan error         // 응답(PUBACK/PUBCOMP/SUBACK)을 기다리는 패킷
+	handlers  map[string]mqttMessageHandler // 구독 토픽 필터 → 처리 함수
+
+	writeMutex sync.Mutex
+}
+
+func newMQTTClient(config mqttConfig) *mqttClient {
+	return &mqttClient{
+		config:   config,
+		inflight: make(map[uint16]chan error),
+		handlers: make(map[string]mqttMessageHandler),
+		closed:   make(chan struct{}),
+	}
+}
+
+// 남은 길이는 7비트씩 나눠 최대 4바이트로 인코딩합니다.
+func encodeRemainingLength(n int) []byte {
+	var out []byte
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if n == 0 {
+			return out
+		}
+	}
+}
+
+func mqttString(s string) []byte {
+	out := make([]byte, 2, 2+len(s))
+	binary.BigEndian.PutUint16(out, uint16(len(s)))
+	return append(out, s...)
+}
+
+func readMQTTPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, fmt.Errorf("잘못된 MQTT 패킷 길이")
+		}
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7F) * multiplier
+		multiplier *= 128
+		if b&0x80 == 0 {
+			break
+		}
+	}
+	if length > mqttMaxPacketSize {
+		return 0, nil, fmt.Errorf("MQTT 패킷이 너무 큽니다 (%d바이트)", length)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+func (c *mqttClient) writePacket(conn net.Conn, header byte, body []byte) error {
+	packet := append([]byte{header}, encodeRemainingLength(len(body))...)
+	packet = append(packet, body...)
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+	conn.SetWriteDeadline(time.Now().Add(mqttAckTimeout))
+	_, err := conn.Write(packet)
+	return err
+}
+
+func (c *mqttClient) dial() (net.Conn, error) {
+	broker := c.config.Broker
+	if !strings.Contains(broker, "://") {
+		broker = "tcp://" + broker
+	}
+	u, err := url.Parse(broker)
+	if err != nil {
+		return nil, fmt.Errorf("잘못된 MQTT_BROKER: %v", err)
+	}
+	dialer := &net.Dialer{Timeout: mqttDialTimeout}
+	switch u.Scheme {
+	case "tcp", "mqtt":
+		host := u.Host
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "1883")
+		}
+		return dialer.Dial("tcp", host)
+	case "ssl", "tls", "mqtts":
+		host := u.Host
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "8883")
+		}
+		return tls.DialWithDialer(dialer, "tcp", host, &tls.Config{ServerName: u.Hostname()})
+	default:
+		return nil, fmt.Errorf("지원하지 않는 MQTT 스킴: %s", u.Scheme)
+	}
+}
+
+// connect는 브로커에 접속해 CONNECT를 보내고 CONNACK을 확인합니다.
+func (c *mqttClient) connect() (net.Conn, *bufio.Reader, error) {
+	conn, err := c.dial()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	flags := byte(0x02) // clean session
+	var payload []byte
+	payload = append(payload, mqttString(c.config.ClientID)...)
+	if c.config.WillTopic != "" {
+		// will flag + will retain + will QoS
+		flags |= 0x04 | 0x20 | c.config.QoS<<3
+		payload = append(payload, mqttString(c.config.WillTopic)...)
+		payload = append(payload, mqttString(c.config.WillPayload)...)
+	}
+	if c.config.Username != "" {
+		flags |= 0x80
+		payload = append(payload, mqttString(c.config.Username)...)
+		if c.config.Password != "" {
+			flags |= 0x40
+			payload = append(payload, mqttString(c.config.Password)...)
+		}
+	}
+	body := append(mqttString("MQTT"), 4, flags, 0, 0)
+	binary.BigEndian.PutUint16(body[len(body)-2:], uint16(c.config.KeepAlive/time.Second))
+	body = append(body, payload...)
+
+	if err := c.writePacket(conn, mqttConnect<<4, body); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	reader := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(mqttAckTimeout))
+	header, ack, err := readMQTTPacket(reader)
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("CONNACK 수신 실패: %v", err)
+	}
+	if header>>4 != mqttConnack || len(ack) != 2 {
+		conn.Close()
+		return nil, nil, fmt.Errorf("CONNACK 대신 패킷 %d를 받았습니다", header>>4)
+	}
+	if ack[1] != 0 {
+		conn.Close()
+		return nil, nil, fmt.Errorf("브로커가 연결을 거부했습니다: %s", mqttConnackErrors[ack[1]])
+	}
+	return conn, reader, nil
+}
+
+// Run은 연결을 유지하는 루프입니다. 별도 고루틴에서 실행하고, Close를 부르면 끝납니다.
+// 연결에 실패했을 때뿐 아니라 끊겼을 때도 대기한 뒤 다시 연결합니다. 브로커가 CONNECT를 받자마자
+// 끊어 버리는 경우 쉬지 않고 재연결하지 않도록, 대기 시간은 연결이 한동안 유지되었을 때만 처음으로 되돌립니다.
+func (c *mqttClient) Run() {
+	minBackoff := c.config.ReconnectMin
+	if minBackoff <= 0 {
+		minBackoff = time.Second
+	}
+	backoff := minBackoff
+	wait := func() bool {
+		select {
+		case <-c.closed:
+			return false
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > c.config.ReconnectMax {
+			backoff = c.config.ReconnectMax
+		}
+		return true
+	}
+
+	for {
+		select {
+		case <-c.closed:
+			return
+		default:
+		}
+		conn, reader, err := c.connect()
+		if err != nil {
+			c.mutex.Lock()
+			c.lastError = err.Error()
+			c.mutex.Unlock()
+			log.Printf("MQTT 연결 실패, %v 후 다시 시도합니다: %v", backoff, err)
+			if !wait() {
+				return
+			}
+			continue
+		}
+		connectedAt := time.Now()
+		log.Printf("MQTT 브로커 연결됨: %s", c.config.Broker)
+
+		c.mutex.Lock()
+		c.conn, c.connected, c.lastError = conn, true, ""
+		select {
+		case <-c.closed:
+			// 연결하는 동안 Close가 불렸으면 바로 끊습니다.
+			conn.Close()
+		default:
+		}
+		filters := make([]string, 0, len(c.handlers))
+		for filter := range c.handlers {
+			filters = append(filters, filter)
+		}
+		c.mutex.Unlock()
+
+		done := make(chan struct{})
+		go c.keepAlive(conn, done)
+		go func() {
+			// 구독 복구와 OnConnect는 응답을 기다리므로 읽기 루프와 따로 돌립니다.
+			for _, filter := range filters {
+				if err := c.sendSubscribe(filter); err != nil {
+					log.Printf("MQTT 구독 실패 (%s): %v", filter, err)
+				}
+			}
+			if c.OnConnect != nil {
+				c.OnConnect()
+			}
+		}()
+
+		err = c.readLoop(conn, reader)
+		close(done)
+		conn.Close()
+
+		c.mutex.Lock()
+		c.connected = false
+		c.lastError = err.Error()
+		for id, ch := range c.inflight {
+			ch <- errMQTTNotConnected
+			delete(c.inflight, id)
+		}
+		c.mutex.Unlock()
+
+		if time.Since(connectedAt) >= mqttStableConnection {
+			backoff = minBackoff
+		}
+		log.Printf("MQTT 연결 끊김, %v 후 다시 연결합니다: %v", backoff, err)
+		if !wait() {
+			return
+		}
+	}
+}
+
+// Close는 연결을 끊고 Run을 끝냅니다.
+func (c *mqttClient) Close() {
+	c.mutex.Lock()
+	select {
+	case <-c.closed:
+	default:
+		close(c.closed)
+	}
+	conn := c.conn
+	c.mutex.Unlock()
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+func (c *mqttClient) keepAlive(conn net.Conn, done chan struct{}) {
+	ticker := time.NewTicker(c.config.KeepAlive / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := c.writePacket(conn, mqttPingreq<<4, nil); err != nil {
+				conn.Close()
+				return
+			}
+		}
+	}
+}
+
+func (c *mqttClient) readLoop(conn net.Conn, reader *bufio.Reader) error {
+	for {
+		// keepAlive의 1.5배 동안 아무 패킷(PINGRESP 포함)도 없으면 끊긴 것으로 봅니다.
+		conn.SetReadDeadline(time.Now().Add(c.config.KeepAlive * 3 / 2))
+		header, body, err := readMQTTPacket(reader)
+		if err != nil {
+			return err
+		}
+		packetType := header >> 4
+		var id uint16
+		switch packetType {
+		case mqttPuback, mqttPubrec, mqttPubrel, mqttPubcomp, mqttSuback:
+			// 패킷 ID가 없는 응답은 잘못된 패킷이므로 연결을 끊고 다시 연결합니다.
+			if len(body) < 2 {
+				return fmt.Errorf("잘못된 MQTT 패킷 (종류 %d, 길이 %d)", packetType, len(body))
+			}
+			id = binary.BigEndian.Uint16(body)
+		}
+
+		switch packetType {
+		case mqttPublish:
+			if err := c.handlePublish(conn, header, body); err != nil {
+				return err
+			}
+		case mqttPuback, mqttPubcomp:
+			c.complete(id, nil)
+		case mqttPubrec:
+			if err := c.writePacket(conn, mqttPubrel<<4|0x02, body[:2]); err != nil {
+				return err
+			}
+		case mqttPubrel:
+			if err := c.writePacket(conn, mqttPubcomp<<4, body[:2]); err != nil {
+				return err
+			}
+		case mqttSuback:
+			var err error
+			if len(body) > 2 && body[2] == 0x80 {
+				err = fmt.Errorf("MQTT 구독이 거부되었습니다")
+			}
+			c.complete(id, err)
+		case mqttPingresp:
+		default:
+			log.Printf("MQTT: 처리하지 않는 패킷 %d", packetType)
+		}
+	}
+}
+
+// 받은 PUBLISH를 구독 처리 함수로 넘깁니다.
+// QoS 2 메시지도 PUBREC을 보낼 때 바로 전달하므로 재전송 시 한 번 더 처리될 수 있습니다.
+func (c *mqttClient) handlePublish(conn net.Conn, header byte, body []byte) error {
+	if len(body) < 2 {
+		return fmt.Errorf("잘못된 PUBLISH 패킷")
+	}
+	qos := (header >> 1) & 0x03
+	topicLen := int(binary.BigEndian.Uint16(body))
+	rest := body[2:]
+	if len(rest) < topicLen {
+		return fmt.Errorf("잘못된 PUBLISH 패킷")
+	}
+	topic := string(rest[:topicLen])
+	rest = rest[topicLen:]
+	if qos > 0 {
+		if len(rest) < 2 {
+			return fmt.Errorf("잘못된 PUBLISH 패킷")
+		}
+		ack := byte(mqttPuback << 4)
+		if qos == 2 {
+			ack = mqttPubrec << 4
+		}
+		if err := c.writePacket(conn, ack, rest[:2]); err != nil {
+			return err
+		}
+		rest = rest[2:]
+	}
+
+	c.mutex.Lock()
+	var matched []mqttMessageHandler
+	for filter, handler := range c.handlers {
+		if mqttTopicMatches(filter, topic) {
+			matched = append(matched, handler)
+		}
+	}
+	c.mutex.Unlock()
+	for _, handler := range matched {
+		go handler(topic, rest)
+	}
+	return nil
+}
+
+// "home/+/temp", "home/#" 같은 와일드카드 필터를 지원합니다.
+// $SYS처럼 $로 시작하는 토픽은 맨 앞이 와일드카드인 필터와 맞지 않습니다. (MQTT 3.1.1 4.7.2)
+// "a/#"은 "a" 자신과도 맞습니다.
+func mqttTopicMatches(filter, topic string) bool {
+	if strings.HasPrefix(topic, "$") && (strings.HasPrefix(filter, "+") || strings.HasPrefix(filter, "#")) {
+		return false
+	}
+	f := strings.Split(filter, "/")
+	t := strings.Split(topic, "/")
+	for i, part := range f {
+		if part == "#" {
+			return true
+		}
+		if i >= len(t) || (part != "+" && part != t[i]) {
+			return false
+		}
+	}
+	return len(f) == len(t)
+}
+
+func (c *mqttClient) complete(id uint16, err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if ch, ok := c.inflight[id]; ok {
+		ch <- err
+		delete(c.inflight, id)
+	}
+}
+
+// 응답을 기다릴 패킷 ID를 발급합니다. 0은 쓸 수 없습니다.
+func (c *mqttClient) register() (net.Conn, uint16, chan error, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if !c.connected {
+		return nil, 0, nil, errMQTTNotConnected
+	}
+	c.nextID++
+	if c.nextID == 0 {
+		c.nextID = 1
+	}
+	ch := make(chan error, 1)
+	c.inflight[c.nextID] = ch
+	return c.conn, c.nextID, ch, nil
+}
+
+func (c *mqttClient) await(id uint16, ch chan error) error {
+	select {
+	case err := <-ch:
+		return err
+	case <-time.After(mqttAckTimeout):
+		c.mutex.Lock()
+		delete(c.inflight, id)
+		c.mutex.Unlock()
+		return fmt.Errorf("MQTT 응답 시간 초과 (패킷 %d)", id)
+	}
+}
+
+// Publish는 설정된 QoS로 메시지를 발행합니다. QoS 1/2는 브로커의 확인을 기다립니다.
+// 끊긴 동안의 메시지는 다시 보내지 않습니다. 재연결 시 OnConnect에서 현재 상태를 다시 발행합니다.
+func (c *mqttClient) Publish(topic string, payload []byte, retain bool) error {
+	qos := c.config.QoS
+	header := byte(mqttPublish<<4) | qos<<1
+	if retain {
+		header |= 0x01
+	}
+	body := mqttString(topic)
+
+	if qos == 0 {
+		c.mutex.Lock()
+		conn, connected := c.conn, c.connected
+		c.mutex.Unlock()
+		if !connected {
+			return errMQTTNotConnected
+		}
+		return c.writePacket(conn, header, append(body, payload...))
+	}
+
+	conn, id, ch, err := c.register()
+	if err != nil {
+		return err
+	}
+	body = binary.BigEndian.AppendUint16(body, id)
+	if err := c.writePacket(conn, header, append(body, payload...)); err != nil {
+		c.complete(id, err)
+		return err
+	}
+	return c.await(id, ch)
+}
+
+// Subscribe는 토픽 필터를 구독합니다. 연결되어 있지 않으면 다음 연결 때 구독합니다.
+func (c *mqttClient) Subscribe(filter string, handler mqttMessageHandler) error {
+	c.mutex.Lock()
+	c.handlers[filter] = handler
+	connected := c.connected
+	c.mutex.Unlock()
+	if !connected {
+		return nil
+	}
+	return c.sendSubscribe(filter)
+}
+
+func (c *mqttClient) sendSubscribe(filter string) error {
+	conn, id, ch, err := c.register()
+	if err != nil {
+		return err
+	}
+	body := binary.BigEndian.AppendUint16(nil, id)
+	body = append(body, mqttString(filter)...)
+	body = append(body, c.config.QoS)
+	if err := c.writePacket(conn, mqttSubscribe<<4|0x02, body); err != nil {
+		c.complete(id, err)
+		return err
+	}
+	return c.await(id, ch)
+}
+
+// Status는 연결 여부와 마지막 오류를 반환합니다.
+func (c *mqttClient) Status() (bool, string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.connected, c.lastError
+}
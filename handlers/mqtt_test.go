@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestEncodeRemainingLength(t *testing.T) {
+	tests := []struct {
+		n    int
+		want []byte
+	}{
+		{0, []byte{0x00}},
+		{127, []byte{0x7F}},
+		{128, []byte{0x80, 0x01}},
+		{16383, []byte{0xFF, 0x7F}},
+		{16384, []byte{0x80, 0x80, 0x01}},
+		{268435455, []byte{0xFF, 0xFF, 0xFF, 0x7F}},
+	}
+	for _, tt := range tests {
+		if got := encodeRemainingLength(tt.n); !bytes.Equal(got, tt.want) {
+			t.Errorf("encodeRemainingLength(%d) = % x, want % x", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestReadMQTTPacketRoundTrip(t *testing.T) {
+	for _, size := range []int{0, 2, 127, 128, 20000} {
+		body := bytes.Repeat([]byte{0xAB}, size)
+		packet := append([]byte{mqttPublish<<4 | 0x02}, encodeRemainingLength(size)...)
+		packet = append(packet, body...)
+
+		header, got, err := readMQTTPacket(bufio.NewReader(bytes.NewReader(packet)))
+		if err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+		if header != mqttPublish<<4|0x02 || !bytes.Equal(got, body) {
+			t.Errorf("size %d: header %x, body %d bytes", size, header, len(got))
+		}
+	}
+}
+
+func TestReadMQTTPacketErrors(t *testing.T) {
+	tests := map[string][]byte{
+		"길이 5바이트": {mqttPublish << 4, 0x80, 0x80, 0x80, 0x80, 0x01},
+		"본문이 짧음":  {mqttPuback << 4, 0x02, 0x00},
+		"헤더만 있음":  {mqttPuback << 4},
+		"빈 스트림":   {},
+	}
+	for name, packet := range tests {
+		if _, _, err := readMQTTPacket(bufio.NewReader(bytes.NewReader(packet))); err == nil {
+			t.Errorf("%s: 오류가 나야 합니다", name)
+		}
+	}
+}
+
+// 본문이 다 와 있어도 최대 크기를 넘는 패킷은 받지 않습니다.
+func TestReadMQTTPacketTooLarge(t *testing.T) {
+	size := mqttMaxPacketSize + 1
+	packet := append([]byte{mqttPublish << 4}, encodeRemainingLength(size)...)
+	packet = append(packet, make([]byte, size)...)
+	if _, _, err := readMQTTPacket(bufio.NewReader(bytes.NewReader(packet))); err == nil {
+		t.Errorf("%d바이트 패킷을 받았습니다", size)
+	}
+}
+
+func TestMQTTString(t *testing.T) {
+	if got, want := mqttString("날씨"), []byte{0x00, 0x06, 0xEB, 0x82, 0xA0, 0xEC, 0x94, 0xA8}; !bytes.Equal(got, want) {
+		t.Errorf("mqttString = % x, want % x", got, want)
+	}
+}
+
+func TestMQTTTopicMatches(t *testing.T) {
+	tests := []struct {
+		filter, topic string
+		want          bool
+	}{
+		{"a/b", "a/b", true},
+		{"a/b", "a/c", false},
+		{"a/b", "a/b/c", false},
+		{"a/+", "a/b", true},
+		{"a/+", "a", false},
+		{"a/+", "a/b/c", false},
+		{"a/+/c", "a/b/c", true},
+		{"+/+", "/b", true},
+		{"a/#", "a", true},
+		{"a/#", "a/b/c", true},
+		{"a/#", "b/c", false},
+		{"#", "a/b", true},
+		{"#", "$SYS/broker/uptime", false},
+		{"+/broker/uptime", "$SYS/broker/uptime", false},
+		{"$SYS/#", "$SYS/broker/uptime", true},
+	}
+	for _, tt := range tests {
+		if got := mqttTopicMatches(tt.filter, tt.topic); got != tt.want {
+			t.Errorf("mqttTopicMatches(%q, %q) = %v, want %v", tt.filter, tt.topic, got, tt.want)
+		}
+	}
+}
+
+// 브로커 쪽 연결에 패킷을 써 넣고 readLoop가 어떻게 끝나는지 봅니다.
+func runReadLoop(t *testing.T, c *mqttClient, packets ...[]byte) error {
+	t.Helper()
+	client, broker := net.Pipe()
+	defer client.Close()
+	defer broker.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- c.readLoop(client, bufio.NewReader(client)) }()
+
+	go func() {
+		for _, p := range packets {
+			broker.Write(p)
+		}
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(2 * time.Second):
+		t.Fatal("readLoop가 끝나지 않았습니다")
+		return nil
+	}
+}
+
+func TestReadLoopDropsShortAck(t *testing.T) {
+	for _, packetType := range []byte{mqttPuback, mqttPubrec, mqttPubrel, mqttPubcomp, mqttSuback} {
+		c := newMQTTClient(mqttConfig{KeepAlive: time.Minute})
+		err := runReadLoop(t, c, []byte{packetType << 4, 0x00})
+		if err == nil {
+			t.Errorf("종류 %d: 패킷 ID가 없으면 연결을 끊어야 합니다", packetType)
+		}
+	}
+}
+
+func TestHandlePublishQoS1(t *testing.T) {
+	c := newMQTTClient(mqttConfig{KeepAlive: time.Minute})
+	received := make(chan string, 1)
+	c.handlers["home/+/set"] = func(topic string, payload []byte) {
+		received <- topic + "=" + string(payload)
+	}
+
+	client, broker := net.Pipe()
+	defer client.Close()
+	defer broker.Close()
+
+	body := append(mqttString("home/light/set"), 0x12, 0x34)
+	body = append(body, "ON"...)
+	go func() {
+		if err := c.handlePublish(client, mqttPublish<<4|0x02, body); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	header, ack, err := readMQTTPacket(bufio.NewReader(broker))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header != mqttPuback<<4 || !bytes.Equal(ack, []byte{0x12, 0x34}) {
+		t.Errorf("PUBACK = %x % x", header, ack)
+	}
+	select {
+	case got := <-received:
+		if got != "home/light/set=ON" {
+			t.Errorf("받은 메시지 = %q", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("구독 처리 함수가 호출되지 않았습니다")
+	}
+}
+
+func TestHandlePublishMalformed(t *testing.T) {
+	c := newMQTTClient(mqttConfig{KeepAlive: time.Minute})
+	tests := map[string][]byte{
+		"본문 없음":    {},
+		"토픽이 잘림":   {0x00, 0x05, 'a'},
+		"패킷 ID 없음": mqttString("a"),
+	}
+	for name, body := range tests {
+		if err := c.handlePublish(nil, mqttPublish<<4|0x02, body); err == nil {
+			t.Errorf("%s: 오류가 나야 합니다", name)
+		}
+	}
+}
+
+// testBroker는 127.0.0.1에서 CONNECT/SUBSCRIBE/PUBLISH에 응답하는 최소한의 브로커입니다.
+// onConnect가 있으면 CONNACK을 보낸 뒤 그 연결을 넘겨받습니다. (true를 반환하면 바로 끊습니다)
+type testBroker struct {
+	listener  net.Listener
+	accepts   chan time.Time
+	published chan string
+	onConnect func(conn net.Conn) bool
+}
+
+func newTestBroker(t *testing.T, onConnect func(conn net.Conn) bool) *testBroker {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("로컬 브로커를 열 수 없습니다: %v", err)
+	}
+	b := &testBroker{
+		listener:  listener,
+		accepts:   make(chan time.Time, 100),
+		published: make(chan string, 10),
+		onConnect: onConnect,
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+func (b *testBroker) url() string { return "tcp://" + b.listener.Addr().String() }
+
+func (b *testBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	header, _, err := readMQTTPacket(reader)
+	if err != nil || header>>4 != mqttConnect {
+		return
+	}
+	select {
+	case b.accepts <- time.Now():
+	default:
+	}
+	conn.Write([]byte{mqttConnack << 4, 0x02, 0x00, 0x00})
+	if b.onConnect != nil && b.onConnect(conn) {
+		return
+	}
+	for {
+		header, body, err := readMQTTPacket(reader)
+		if err != nil {
+			return
+		}
+		switch header >> 4 {
+		case mqttSubscribe:
+			conn.Write([]byte{mqttSuback << 4, 0x03, body[0], body[1], 0x01})
+		case mqttPublish:
+			topicLen := int(body[0])<<8 | int(body[1])
+			topic := string(body[2 : 2+topicLen])
+			rest := body[2+topicLen:]
+			if (header>>1)&0x03 > 0 {
+				conn.Write([]byte{mqttPuback << 4, 0x02, rest[0], rest[1]})
+				rest = rest[2:]
+			}
+			b.published <- topic + "=" + string(rest)
+		case mqttPingreq:
+			conn.Write([]byte{mqttPingresp << 4, 0x00})
+		}
+	}
+}
+
+func TestMQTTClientLocalBroker(t *testing.T) {
+	// 구독이 끝나면 브로커가 구독 토픽으로 메시지를 하나 보냅니다.
+	broker := newTestBroker(t, func(conn net.Conn) bool {
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			body := append(mqttString("home/indoor/living"), 0x00, 0x07)
+			body = append(body, `{"t":1}`...)
+			packet := append([]byte{mqttPublish<<4 | 0x02}, encodeRemainingLength(len(body))...)
+			conn.Write(append(packet, body...))
+		}()
+		return false
+	})
+
+	c := newMQTTClient(mqttConfig{Broker: broker.url(), ClientID: "test", KeepAlive: time.Minute, QoS: 1, ReconnectMax: time.Second})
+	received := make(chan string, 1)
+	c.Subscribe("home/indoor/+", func(topic string, payload []byte) {
+		received <- topic + "=" + string(payload)
+	})
+	c.OnConnect = func() {
+		if err := c.Publish("weather/status", []byte("online"), true); err != nil {
+			t.Errorf("발행 실패: %v", err)
+		}
+	}
+	go c.Run()
+	defer c.Close()
+
+	for _, want := range []struct {
+		ch   chan string
+		want string
+	}{
+		{broker.published, "weather/status=online"},
+		{received, `home/indoor/living={"t":1}`},
+	} {
+		select {
+		case got := <-want.ch:
+			if got != want.want {
+				t.Errorf("받은 메시지 = %q, want %q", got, want.want)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("%q를 받지 못했습니다", want.want)
+		}
+	}
+	if connected, _ := c.Status(); !connected {
+		t.Error("연결된 상태여야 합니다")
+	}
+}
+
+// CONNACK을 보내자마자 끊는 브로커에도 재연결 간격이 점점 늘어나야 합니다.
+func TestMQTTClientBacksOffAfterDisconnect(t *testing.T) {
+	broker := newTestBroker(t, func(net.Conn) bool { return true })
+	c := newMQTTClient(mqttConfig{
+		Broker:       broker.url(),
+		ClientID:     "test",
+		KeepAlive:    time.Minute,
+		ReconnectMin: 50 * time.Millisecond,
+		ReconnectMax: 400 * time.Millisecond,
+	})
+	go c.Run()
+	defer c.Close()
+
+	time.Sleep(time.Second)
+	c.Close()
+	var times []time.Time
+	for len(broker.accepts) > 0 {
+		times = append(times, <-broker.accepts)
+	}
+	// 50+100+200+400+400ms 대기면 1초 동안 5번 안팎입니다. 대기 없이 재연결하면 수백 번이 됩니다.
+	if len(times) < 3 || len(times) > 7 {
+		t.Fatalf("1초 동안 %d번 연결했습니다", len(times))
+	}
+	for i := 2; i < len(times); i++ {
+		if gap, prev := times[i].Sub(times[i-1]), times[i-1].Sub(times[i-2]); gap < prev*3/2 && gap < 350*time.Millisecond {
+			t.Errorf("%d번째 재연결 간격 %v가 앞선 간격 %v보다 늘지 않았습니다", i, gap, prev)
+		}
+	}
+}
@@ -17,56 +17,104 @@ import (
 )
 
 type NewsCache struct {
+    Sections map[string]newsCacheEntry // 섹션 ID별 캐시
+    mutex    sync.RWMutex
+}
+
+type newsCacheEntry struct {
     Data      []models.NewsItem // NewsItem 슬라이스를 캐싱
     ExpiresAt time.Time
-    mutex     sync.RWMutex
 }
 
 var (
-    newsCache = &NewsCache{}
+    newsCache = &NewsCache{Sections: make(map[string]newsCacheEntry)}
 )
 
-// 중복 제거를 위해 뉴스 제목의 [속보], (종합) 등을 제거하는 정규표현식
-var newsTitleCleaner = regexp.MustCompile(`^\[.*?\]|\(.*?\)`)
+// 섹션 하나에 보여줄 수 있는 최대 기사 수
+const maxNewsCount = 20
+
+// 섹션 설정 파일이 없을 때 쓰는 기본 섹션 (예전의 "뉴스" 검색과 같습니다)
+var defaultNewsSection = models.NewsSection{ID: "top", Name: "주요 뉴스", Query: "뉴스", Sort: "sim", Count: 5, TTLMinutes: 30}
+
+// 주제별 섹션 설정. 예: [{"name": "경제", "query": "경제", "sort": "date", "count": 3, "ttlMinutes": 60}]
+// feeds에 RSS/Atom 주소를 적으면 Naver 검색 결과와 합쳐 보여줍니다.
+func newsSectionsPath() string { return dataPath("news_sections.json") }
 
-// Naver API 응답에 포함된 HTML 태그(<b>, &lt; 등)를 제거하는 헬퍼 함수
-func cleanHtmlTags(s string) string {
-	s = strings.ReplaceAll(s, "<b>", "")
-	s = strings.ReplaceAll(s, "</b>", "")
-	s = strings.ReplaceAll(s, "&quot;", "\"")
-	s = strings.ReplaceAll(s, "&lt;", "<")
-	s = strings.ReplaceAll(s, "&gt;", ">")
-	return s
+// NEWS_FEEDS(쉼표로 구분한 RSS/Atom 주소)는 섹션 설정이 없을 때 기본 섹션에 더합니다.
+func defaultNewsFeeds() []string {
+	var feeds []string
+	for _, feed := range strings.Split(os.Getenv("NEWS_FEEDS"), ",") {
+		if feed = strings.TrimSpace(feed); feed != "" {
+			feeds = append(feeds, feed)
+		}
+	}
+	return feeds
 }
 
-// 기사 목록에서 중복을 제거하고 원하는 개수만큼 반환하는 함수
+// newsSections는 설정된 섹션 목록입니다. 빠진 값은 기본값으로 채우고, 검색어도 피드도 없는 섹션은 건너뜁니다.
+func newsSections() []models.NewsSection {
+	var sections []models.NewsSection
+	if err := readJSONFile(newsSectionsPath(), &sections); err != nil {
+		log.Printf("뉴스 섹션 설정 불러오기 실패: %v", err)
+	}
+	var result []models.NewsSection
+	seen := make(map[string]bool)
+	for _, section := range sections {
+		if strings.TrimSpace(section.Query) == "" && len(section.Feeds) == 0 {
+			log.Printf("검색어도 피드도 없는 뉴스 섹션은 건너뜁니다: %q", section.Name)
+			continue
+		}
+		if section.Name == "" {
+			section.Name = section.Query
+		}
+		if section.Name == "" {
+			section.Name = "뉴스"
+		}
+		if section.ID == "" {
+			section.ID = section.Name
+		}
+		if seen[section.ID] {
+			log.Printf("중복된 뉴스 섹션 id는 건너뜁니다: %q", section.ID)
+			continue
+		}
+		seen[section.ID] = true
+		if section.Sort != "sim" && section.Sort != "date" {
+			section.Sort = defaultNewsSection.Sort
+		}
+		switch {
+		case section.Count <= 0:
+			section.Count = defaultNewsSection.Count
+		case section.Count > maxNewsCount:
+			log.Printf("뉴스 섹션 %q의 기사 수 %d를 %d개로 줄입니다", section.ID, section.Count, maxNewsCount)
+			section.Count = maxNewsCount
+		}
+		if section.TTLMinutes <= 0 {
+			section.TTLMinutes = defaultNewsSection.TTLMinutes
+		}
+		result = append(result, section)
+	}
+	if len(result) == 0 {
+		section := defaultNewsSection
+		section.Feeds = defaultNewsFeeds()
+		return []models.NewsSection{section}
+	}
+	return result
+}
+
+// 중복 제거를 위해 뉴스 제목의 [속보], (종합) 등을 제거하는 정규표현식
+var newsTitleCleaner = regexp.MustCompile(`^\[.*?\]|\(.*?\)`)
+
+// 기사 목록에서 같은 사건을 다룬 기사를 묶고, 묶음마다 대표 기사 하나씩 원하는 개수만큼 반환하는 함수
+// (제목이 조금씩 달라도 NEWS_SIMILARITY_THRESHOLD 이상 비슷하면 같은 묶음입니다. dedupe.go 참고)
 func filterUniqueArticles(items []models.NewsItem, maxItems int) []models.NewsItem {
 	// 최종 반환될 고유 기사 슬라이스
 	uniqueArticles := make([]models.NewsItem, 0, maxItems)
-	
-	// 중복 체크를 위한 맵 (Key: 정리된 기사 제목)
-	addedTitles := make(map[string]bool)
-
-	for _, item := range items {
-		// 1. <b> 태그 등 HTML 먼저 제거
-		cleanedTitle := cleanHtmlTags(item.Title)
-		
-		// 2. [속보] (종합) 같은 머릿말 제거
-		cleanedTitle = newsTitleCleaner.ReplaceAllString(cleanedTitle, "")
-		
-		// 3. 앞뒤 공백 제거
-		cleanedTitle = strings.TrimSpace(cleanedTitle)
-
-		// 4. 이미 맵에 존재하는 제목인지 확인
-		if _, exists := addedTitles[cleanedTitle]; exists {
-			continue // 이미 추가된 기사(중복)이므로 건너뜀
-		}
 
-		// 5. 새로운 기사인 경우
-		addedTitles[cleanedTitle] = true // 맵에 등록
-		uniqueArticles = append(uniqueArticles, item) // 결과 슬라이스에 추가
+	// 묶음 크기("외 N건")를 알아야 하므로 모든 기사를 먼저 묶습니다.
+	for _, cluster := range clusterArticles(items, newsSimilarityThreshold()) {
+		uniqueArticles = append(uniqueArticles, cluster.representative())
 
-		// 6. 원하는 개수(5개)를 채웠으면 반복 중단
+		// 원하는 개수를 채웠으면 반복 중단
 		if len(uniqueArticles) >= maxItems {
 			break
 		}
@@ -74,9 +122,17 @@ func filterUniqueArticles(items []models.NewsItem, maxItems int) []models.NewsIt
 	return uniqueArticles
 }
 
-// --- ⭐️ 2. 신규 함수: 실제 API 호출 로직 분리 ---
-// (기존 GetTopNews의 API 호출 부분을 이 함수로 이동)
-func fetchNewsFromAPI() ([]models.NewsItem, error) {
+// --- ⭐️ 2. Naver 검색 API 뉴스 소스 ---
+// 섹션의 검색어(query)로 검색합니다. sort는 sim(정확도순) 또는 date(최신순)입니다.
+type naverSource struct {
+	query string
+	sort  string
+	count int
+}
+
+func (s naverSource) Name() string { return "Naver 검색(" + s.query + ")" }
+
+func (s naverSource) Fetch() ([]models.NewsItem, error) {
 	// 1. Naver API 인증 정보 가져오기
 	clientID := os.Getenv("NAVER_CLIENT_ID")
 	clientSecret := os.Getenv("NAVER_CLIENT_SECRET")
@@ -85,10 +141,16 @@ func fetchNewsFromAPI() ([]models.NewsItem, error) {
 		return nil, fmt.Errorf("Naver API ID 또는 Secret이 설정되지 않았습니다")
 	}
 
-	// 2. 검색어 설정 및 API URL 준비
-	query := "뉴스"
-	escapedQuery := url.QueryEscape(query)
-	apiURL := fmt.Sprintf("https://openapi.naver.com/v1/search/news.json?query=%s&display=20&sort=sim", escapedQuery)
+	// 2. 검색어 설정 및 API URL 준비 (중복을 걸러낼 여유분까지 넉넉히 받습니다)
+	display := s.count * 4
+	if display < 20 {
+		display = 20
+	}
+	if display > 100 {
+		display = 100
+	}
+	escapedQuery := url.QueryEscape(s.query)
+	apiURL := fmt.Sprintf("https://openapi.naver.com/v1/search/news.json?query=%s&display=%d&sort=%s", escapedQuery, display, s.sort)
 
 	// 3. HTTP 요청 생성 (GET)
 	req, err := http.NewRequest("GET", apiURL, nil)
@@ -122,83 +184,105 @@ func fetchNewsFromAPI() ([]models.NewsItem, error) {
 		return nil, fmt.Errorf("Naver JSON 파싱 실패: %v", err)
 	}
 
-	// 7. API 응답(20개)에서 중복을 제거하고 5개만 필터링
-	uniqueArticles := filterUniqueArticles(newsResp.Items, 5)
-	
-	return uniqueArticles, nil
+	// 7. 중복 제거는 다른 소스와 합친 뒤에 합니다 (fetchFromSources)
+	return newsResp.Items, nil
 }
 
-func getNewsFromCache() ([]models.NewsItem, bool) {
+func getNewsFromCache(id string) ([]models.NewsItem, bool) {
 	newsCache.mutex.RLock()
 	defer newsCache.mutex.RUnlock()
-	if time.Now().Before(newsCache.ExpiresAt) {
-		log.Println("캐시된 뉴스 데이터 사용") // 확인용 로그
-		return newsCache.Data, true
+	entry, ok := newsCache.Sections[id]
+	if ok && time.Now().Before(entry.ExpiresAt) {
+		log.Printf("캐시된 뉴스 데이터 사용 (%s)", id) // 확인용 로그
+		return entry.Data, true
 	}
 	return nil, false
 }
 
-func setNewsCache(data []models.NewsItem) {
+func setNewsCache(section models.NewsSection, data []models.NewsItem) {
 	newsCache.mutex.Lock()
 	defer newsCache.mutex.Unlock()
-	newsCache.Data = data
-	// ⭐️ 1. 수정: 만료 시간을 30분으로 설정
-	newsCache.ExpiresAt = time.Now().Add(30 * time.Minute)
-	log.Printf("새로운 뉴스 데이터 캐시 저장 (만료 시간: %v)", newsCache.ExpiresAt)
+	// 섹션마다 설정한 시간(기본 30분) 동안 캐시합니다.
+	expiresAt := time.Now().Add(time.Duration(section.TTLMinutes) * time.Minute)
+	newsCache.Sections[section.ID] = newsCacheEntry{Data: data, ExpiresAt: expiresAt}
+	log.Printf("새로운 뉴스 데이터 캐시 저장 (%s, 만료 시간: %v)", section.ID, expiresAt)
 }
 
 // --- ⭐️ 3. 수정된 캐시 로직 함수 ---
-func fetchAndCacheNews() ([]models.NewsItem, error) {
+func fetchAndCacheNews(section models.NewsSection) ([]models.NewsItem, error) {
 	// 캐시가 있으면 캐시 반환
-	if cachedNews, ok := getNewsFromCache(); ok {
+	if cachedNews, ok := getNewsFromCache(section.ID); ok {
 		return cachedNews, nil
 	}
 
-	// 캐시가 없으면 API 호출 함수(신규)를 호출
-	result, err := fetchNewsFromAPI()
+	// 캐시가 없으면 섹션의 모든 소스에서 가져와 합칩니다
+	result, err := fetchFromSources(section, newsSourcesFor(section))
 	if err != nil {
-		log.Printf("뉴스 데이터 가져오기 실패: %v", err)
+		log.Printf("%s 뉴스 데이터 가져오기 실패: %v", section.Name, err)
 		return nil, err
 	}
 
-	// ⭐️ 4. 오타 수정: setCache -> setNewsCache
 	// API 결과를 캐시에 저장
-	setNewsCache(result)
+	setNewsCache(section, result)
 	return result, nil
 }
+
+// newsView는 "news" 템플릿의 기사 하나입니다.
+type newsView struct {
+	Title, Description, Link, Source string
+	Related                          []string // 같은 사건을 다룬 다른 기사 제목
+}
+
+// newsSectionView는 뉴스 영역에 따로 그리는 섹션 하나입니다. 가져오지 못한 섹션은 Failed입니다.
+type newsSectionView struct {
+	Name   string
+	Items  []newsView
+	Failed bool
+}
+
 // --- ⭐️ 5. 수정된 핸들러 함수 ---
-// (API 호출 로직은 모두 제거되고 캐시 호출 및 렌더링만 남음)
+// 섹션마다 캐시를 확인해 블록을 하나씩 그립니다. 한 섹션이 실패해도 나머지는 보여줍니다.
 func GetTopNews(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
 
-	// 1. 캐시 로직 함수 호출
-	articles, err := fetchAndCacheNews()
-	if err != nil {
-		http.Error(w, "뉴스 정보를 가져올 수 없습니다.", http.StatusInternalServerError)
-		return
+	sections := newsSections()
+	views := make([]newsSectionView, len(sections))
+	descriptionLength := newsDescriptionLength()
+	var wg sync.WaitGroup
+	for i, section := range sections {
+		wg.Add(1)
+		go func(i int, section models.NewsSection) {
+			defer wg.Done()
+			view := newsSectionView{Name: section.Name}
+			// 1. 캐시 로직 함수 호출
+			articles, err := fetchAndCacheNews(section)
+			if err != nil {
+				view.Failed = true
+			}
+			// 2. 제목/요약은 템플릿이 이스케이프하고, 링크는 http/https만 남깁니다
+			for _, item := range articles {
+				view.Items = append(view.Items, newsView{
+					Title:       cleanNewsText(item.Title),
+					Description: truncateGraphemes(cleanNewsText(item.Description), descriptionLength),
+					Link:        safeLink(item.Link),
+					Source:      item.Source,
+					Related:     item.Related,
+				})
+			}
+			views[i] = view
+		}(i, section)
 	}
+	wg.Wait()
 
-	if len(articles) == 0 {
-		fmt.Fprint(w, "<p>가져온 뉴스가 없습니다.</p>")
-		return
+	failed := 0
+	for _, view := range views {
+		if view.Failed {
+			failed++
+		}
 	}
-
-	// 2. HTML 렌더링 (변경 없음)
-	var htmlBuilder strings.Builder
-	for _, item := range articles {
-		title := cleanHtmlTags(item.Title)
-		description := cleanHtmlTags(item.Description)
-
-		htmlBuilder.WriteString(fmt.Sprintf(
-			`<div class="news-item">
-                <h4><a href="%s" target="_blank">%s</a></h4>
-                <p>%s</p>
-            </div>`,
-			item.Link,
-			title,
-			description,
-		))
+	if failed == len(views) {
+		http.Error(w, "뉴스 정보를 가져올 수 없습니다.", http.StatusInternalServerError)
+		return
 	}
-
-	fmt.Fprint(w, htmlBuilder.String())
-}
\ No newline at end of file
+	renderFragment(w, "news", views)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/mail"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// NewsSource는 기사를 가져오는 곳입니다. Naver 검색 API(naverSource)와 RSS/Atom 피드(feedSource)가 있습니다.
+// Fetch는 중복을 거르지 않은 기사를 돌려주고, 섹션의 모든 소스를 합친 뒤 filterUniqueArticles로 거릅니다.
+type NewsSource interface {
+	Name() string
+	Fetch() ([]models.NewsItem, error)
+}
+
+// newsSourcesFor는 섹션 설정으로 소스 목록을 만듭니다. 검색어가 있으면 Naver를 먼저 씁니다.
+func newsSourcesFor(section models.NewsSection) []NewsSource {
+	var sources []NewsSource
+	if strings.TrimSpace(section.Query) != "" {
+		sources = append(sources, naverSource{query: section.Query, sort: section.Sort, count: section.Count})
+	}
+	for _, feed := range section.Feeds {
+		sources = append(sources, feedSource{url: feed})
+	}
+	return sources
+}
+
+// fetchFromSources는 소스들을 동시에 가져와 합칩니다. 일부 소스가 실패해도 나머지로 보여주고,
+// 모두 실패했을 때만 에러를 돌려줍니다. 최신순 섹션은 발행 시각으로 다시 정렬합니다.
+func fetchFromSources(section models.NewsSection, sources []NewsSource) ([]models.NewsItem, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("%s 섹션에 뉴스 소스가 없습니다", section.Name)
+	}
+	results := make([][]models.NewsItem, len(sources))
+	errs := make([]error, len(sources))
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source NewsSource) {
+			defer wg.Done()
+			results[i], errs[i] = source.Fetch()
+		}(i, source)
+	}
+	wg.Wait()
+
+	var merged []models.NewsItem
+	var failures []string
+	for i, source := range sources {
+		if errs[i] != nil {
+			log.Printf("뉴스 소스 %s 가져오기 실패: %v", source.Name(), errs[i])
+			failures = append(failures, fmt.Sprintf("%s: %v", source.Name(), errs[i]))
+			continue
+		}
+		merged = append(merged, results[i]...)
+	}
+	if len(failures) == len(sources) {
+		return nil, fmt.Errorf("모든 뉴스 소스 실패 (%s)", strings.Join(failures, "; "))
+	}
+
+	if section.Sort == "date" {
+		sort.SliceStable(merged, func(i, j int) bool {
+			return newsPublishedAt(merged[i]).After(newsPublishedAt(merged[j]))
+		})
+	}
+	return filterUniqueArticles(merged, section.Count), nil
+}
+
+// newsPublishedAt은 PubDate(RFC 1123 형식)를 읽습니다. 읽을 수 없으면 0시각입니다.
+func newsPublishedAt(item models.NewsItem) time.Time {
+	t, err := mail.ParseDate(item.PubDate)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
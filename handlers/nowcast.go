@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 서버가 꺼져 있던 동안 놓친 관측은 최근 이 시간까지만 채웁니다. (초단기실황은 하루 전까지 조회 가능)
+const nowcastBackfill = 6 * time.Hour
+
+// 초단기실황은 매시 정각 관측값이 40분쯤 제공됩니다. 지금 받을 수 있는 가장 최근 관측 시각을 구합니다.
+func latestNowcastTime(now time.Time) time.Time {
+	now = now.In(seoul).Add(-40 * time.Minute)
+	return time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, seoul)
+}
+
+// getNowcast는 loc 격자의 at 시각 초단기실황(getUltraSrtNcst)을 가져옵니다.
+func getNowcast(loc models.Location, at time.Time) (models.ObservationRecord, error) {
+	apiUrl := fmt.Sprintf(
+		"https://apihub.kma.go.kr/api/typ02/openApi/VilageFcstInfoService_2.0/getUltraSrtNcst?pageNo=1&numOfRows=100&dataType=JSON&base_date=%s&base_time=%s&nx=%d&ny=%d&authKey=%s",
+		at.Format("20060102"),
+		at.Format("1504"),
+		loc.Nx,
+		loc.Ny,
+		getAPIKEY(false),
+	)
+	resp, err := httpClient.Get(apiUrl)
+	if err != nil {
+		return models.ObservationRecord{}, fmt.Errorf("HTTP 요청 실패: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return models.ObservationRecord{}, fmt.Errorf("API 응답 실패: 상태 코드 %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return models.ObservationRecord{}, fmt.Errorf("응답 본문 읽기 실패: %v", err)
+	}
+
+	var nowcastResp models.WeatherResponse
+	if err := json.Unmarshal(body, &nowcastResp); err != nil {
+		return models.ObservationRecord{}, fmt.Errorf("JSON 파싱 실패: %v", err)
+	}
+	items := nowcastResp.Response.Body.Items.Item
+	if len(items) == 0 {
+		return models.ObservationRecord{}, fmt.Errorf("초단기실황 응답이 비어있습니다 (%s)", nowcastResp.Response.Header.ResultMsg)
+	}
+
+	record := models.ObservationRecord{
+		Grid:      locationKey(loc),
+		Time:      at,
+		Values:    make(map[string]string, len(items)),
+		FetchedAt: time.Now(),
+	}
+	for _, item := range items {
+		record.Values[item.Category] = item.ObsrValue
+	}
+	return record, nil
+}
+
+// 관측을 저장할 격자: 집과 프로필/일정에 등록된 장소들
+func observationLocations() []models.Location {
+	seen := make(map[string]bool)
+	var result []models.Location
+	for _, loc := range knownLocations() {
+		if key := locationKey(loc); !seen[key] && loc.Nx != 0 && loc.Ny != 0 {
+			seen[key] = true
+			result = append(result, loc)
+		}
+	}
+	return result
+}
+
+// runNowcastObservation은 매시 관측값을 받아 이력에 저장합니다. 놓친 최근 몇 시간도 함께 채웁니다.
+func runNowcastObservation() error {
+	latest := latestNowcastTime(time.Now())
+	var failed int
+	for _, loc := range observationLocations() {
+		grid := locationKey(loc)
+		for at := latest.Add(-nowcastBackfill); !at.After(latest); at = at.Add(time.Hour) {
+			if _, ok := observationAt(grid, at); ok {
+				continue
+			}
+			record, err := getNowcast(loc, at)
+			if err != nil {
+				log.Printf("%s %s 관측값을 가져오지 못했습니다: %v", loc.Name, at.Format("01/02 15시"), err)
+				failed++
+				continue
+			}
+			if _, err := recordObservation(record); err != nil {
+				return fmt.Errorf("관측 이력 저장 실패: %v", err)
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("관측값 %d건을 가져오지 못했습니다", failed)
+	}
+	return nil
+}
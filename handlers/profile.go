@@ -0,0 +1,341 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+type profileStore struct {
+	profiles []models.Profile
+	loaded   bool
+	mutex    sync.Mutex
+}
+
+var profiles = &profileStore{}
+
+func profilesPath() string { return dataPath("profiles.json") }
+
+func (s *profileStore) list() []models.Profile {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.loaded {
+		s.loaded = true
+		if err := readJSONFile(profilesPath(), &s.profiles); err != nil {
+			log.Printf("프로필 불러오기 실패: %v", err)
+		}
+	}
+	return append([]models.Profile(nil), s.profiles...)
+}
+
+func (s *profileStore) get(id string) (models.Profile, bool) {
+	for _, p := range s.list() {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return models.Profile{}, false
+}
+
+func (s *profileStore) replace(list []models.Profile) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := writeJSONFile(profilesPath(), list); err != nil {
+		return err
+	}
+	s.profiles = list
+	s.loaded = true
+	return nil
+}
+
+func profileRecipient(profileID string) string {
+	if p, ok := profiles.get(profileID); ok && p.RecipientID != "" {
+		return p.RecipientID
+	}
+	return defaultRecipientID
+}
+
+// 출발지가 지정되지 않았으면 집을 출발지로 봅니다.
+func profileOrigin(p models.Profile) models.Location {
+	if p.Origin.Nx == 0 || p.Origin.Ny == 0 {
+		return homeLocation()
+	}
+	if p.Origin.Name == "" {
+		p.Origin.Name = "출발지"
+	}
+	return p.Origin
+}
+
+// 도착지가 지정되지 않았으면 false를 반환합니다.
+func profileDestination(p models.Profile) (models.Location, bool) {
+	if p.Destination.Nx == 0 || p.Destination.Ny == 0 {
+		return models.Location{}, false
+	}
+	if p.Destination.Name == "" {
+		p.Destination.Name = "도착지"
+	}
+	return p.Destination, true
+}
+
+// commuteOccurrence는 특정 날짜에 실제로 돌아오는 통근 시간대 한 번입니다.
+type commuteOccurrence struct {
+	Window models.CommuteWindow
+	Start  time.Time
+	End    time.Time
+}
+
+// 방향을 적지 않은 통근 시간대는 이름에 이런 말이 있으면 귀가로 봅니다.
+var returnCommuteWords = []string{"퇴근", "귀가", "하교", "하원", "복귀"}
+
+// commuteDirection은 통근 시간대의 방향(outbound, return)입니다.
+func commuteDirection(w models.CommuteWindow) string {
+	if w.Direction != "" {
+		return w.Direction
+	}
+	for _, word := range returnCommuteWords {
+		if strings.Contains(w.Label, word) {
+			return models.CommuteReturn
+		}
+	}
+	return models.CommuteOutbound
+}
+
+// from부터 horizon 안에 걸치는 통근 시간대를 시간순으로 반환합니다.
+func commuteOccurrences(p models.Profile, from time.Time, horizon time.Duration) []commuteOccurrence {
+	var result []commuteOccurrence
+	until := from.Add(horizon)
+	for day := 0; day <= int(horizon.Hours()/24)+1; day++ {
+		date := from.AddDate(0, 0, day)
+		midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, seoul)
+		for _, window := range p.Commutes {
+			if len(window.Days) > 0 && !containsInt(window.Days, int(midnight.Weekday())) {
+				continue
+			}
+			startMin, err1 := parseClock(window.Start)
+			endMin, err2 := parseClock(window.End)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			start := midnight.Add(time.Duration(startMin) * time.Minute)
+			end := midnight.Add(time.Duration(endMin) * time.Minute)
+			if !end.After(start) {
+				end = end.AddDate(0, 0, 1)
+			}
+			if end.After(from) && start.Before(until) {
+				result = append(result, commuteOccurrence{Window: window, Start: start, End: end})
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
+
+func containsInt(list []int, n int) bool {
+	for _, item := range list {
+		if item == n {
+			return true
+		}
+	}
+	return false
+}
+
+// [start, end)와 겹치는 1시간 단위 예보 칸을 시간순으로 골라냅니다.
+func slotsBetween(items []models.WeatherItem, start, end time.Time) []models.WeatherItem {
+	var result []models.WeatherItem
+	for _, item := range items {
+		t, err := slotTime(item)
+		if err != nil {
+			continue
+		}
+		if t.Before(end) && t.Add(time.Hour).After(start) {
+			result = append(result, item)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date+result[i].Time < result[j].Date+result[j].Time
+	})
+	return result
+}
+
+// evaluateProfileReminders는 구성원마다 앞으로 24시간 안의 통근 시간대만 보고
+// 출발지와 도착지 예보에 규칙을 적용합니다.
+func evaluateProfileReminders(now time.Time) []models.Reminder {
+	var result []models.Reminder
+	for _, p := range profiles.list() {
+		occurrences := commuteOccurrences(p, now, 24*time.Hour)
+		if len(occurrences) == 0 {
+			continue
+		}
+
+		locations := []models.Location{profileOrigin(p)}
+		if dest, ok := profileDestination(p); ok {
+			locations = append(locations, dest)
+		}
+		for _, loc := range locations {
+			allWeather, err := fetchAndCacheWeatherAt(loc)
+			if err != nil {
+				log.Printf("%s 프로필의 %s 예보를 가져오지 못했습니다: %v", p.Name, loc.Name, err)
+				continue
+			}
+			for _, occ := range occurrences {
+				window := slotsBetween(allWeather, occ.Start, occ.End)
+				for _, reminder := range applyReminderRules(window, now, p.Reminders) {
+					reminder.ID = fmt.Sprintf("%s-%s-%s-%s", p.ID, locationKey(loc), occ.Start.Format("200601021504"), reminder.ID)
+					reminder.ProfileID = p.ID
+					reminder.Title = fmt.Sprintf("%s님 %s길 · %s", p.Name, occ.Window.Label, reminder.Title)
+					reminder.Message = fmt.Sprintf("%s: %s", loc.Name, reminder.Message)
+					result = append(result, reminder)
+				}
+			}
+		}
+	}
+	return result
+}
+
+func validateProfile(p models.Profile) error {
+	if p.ID == "" || p.Name == "" {
+		return fmt.Errorf("id와 name이 필요합니다")
+	}
+	for _, window := range p.Commutes {
+		if _, err := parseClock(window.Start); err != nil {
+			return fmt.Errorf("%s: %v", p.ID, err)
+		}
+		if _, err := parseClock(window.End); err != nil {
+			return fmt.Errorf("%s: %v", p.ID, err)
+		}
+		for _, day := range window.Days {
+			if day < 0 || day > 6 {
+				return fmt.Errorf("%s: 요일은 0(일)~6(토)이어야 합니다", p.ID)
+			}
+		}
+		switch window.Direction {
+		case "", models.CommuteOutbound, models.CommuteReturn:
+		default:
+			return fmt.Errorf("%s: direction은 outbound 또는 return이어야 합니다", p.ID)
+		}
+	}
+	for _, kind := range p.Reminders {
+		known := false
+		for _, rule := range reminderRules {
+			known = known || rule.Kind == kind
+		}
+		if !known {
+			return fmt.Errorf("%s: 알 수 없는 알림 종류 %q", p.ID, kind)
+		}
+	}
+	return nil
+}
+
+// GetProfiles는 구성원 프로필 목록을 JSON으로 반환합니다.
+func GetProfiles(w http.ResponseWriter, r *http.Request) {
+	list := profiles.list()
+	if list == nil {
+		list = []models.Profile{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// PutProfiles는 프로필 목록 전체를 교체하고 data/profiles.json에 저장합니다.
+// 누구에게 어떤 알림이 갈지 바뀌므로 PutRecipients와 같은 INDOOR_TOKEN으로 인증합니다.
+func PutProfiles(w http.ResponseWriter, r *http.Request) {
+	if !indoorAuthorized(r) {
+		http.Error(w, "인증에 실패했습니다.", http.StatusUnauthorized)
+		return
+	}
+	var list []models.Profile
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&list); err != nil {
+		http.Error(w, "잘못된 프로필 형식입니다.", http.StatusBadRequest)
+		return
+	}
+	seen := make(map[string]bool)
+	for _, p := range list {
+		if err := validateProfile(p); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if seen[p.ID] {
+			http.Error(w, fmt.Sprintf("중복된 id: %s", p.ID), http.StatusBadRequest)
+			return
+		}
+		seen[p.ID] = true
+	}
+	if err := profiles.replace(list); err != nil {
+		log.Printf("프로필 저장 실패: %v", err)
+		http.Error(w, "프로필을 저장할 수 없습니다.", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// profileTile은 예보 칸 하나입니다. 통근 시간대에 걸치는 칸은 commute-slot 클래스와 라벨을 붙입니다.
+func profileTile(item models.WeatherItem, label string) tileView {
+	tile := newTile(item)
+	if label != "" {
+		tile.Class, tile.Label = "commute-slot", label
+	}
+	return tile
+}
+
+// GetProfileWeather는 구성원 한 명의 앞으로 24시간 예보를 통근 시간대를 강조해 보여줍니다.
+func GetProfileWeather(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	p, ok := profiles.get(r.URL.Query().Get("id"))
+	if !ok {
+		http.Error(w, "프로필을 찾을 수 없습니다.", http.StatusNotFound)
+		return
+	}
+	allWeather, err := fetchAndCacheWeatherAt(profileOrigin(p))
+	if err != nil {
+		http.Error(w, "날씨 정보를 가져올 수 없습니다.", http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now().In(seoul)
+	occurrences := commuteOccurrences(p, now, 24*time.Hour)
+	labelFor := func(item models.WeatherItem) string {
+		for _, occ := range occurrences {
+			if len(slotsBetween([]models.WeatherItem{item}, occ.Start, occ.End)) > 0 {
+				return occ.Window.Label
+			}
+		}
+		return ""
+	}
+
+	type tileGroup struct {
+		Name  string
+		Tiles []tileView
+	}
+	view := struct {
+		tileGroup
+		Destination *tileGroup
+	}{tileGroup: tileGroup{Name: p.Name}}
+	for _, item := range forecastWindow(allWeather, now, 24*time.Hour) {
+		view.Tiles = append(view.Tiles, profileTile(item, labelFor(item)))
+	}
+
+	if dest, ok := profileDestination(p); ok && len(occurrences) > 0 {
+		destWeather, err := fetchAndCacheWeatherAt(dest)
+		if err != nil {
+			log.Printf("%s 예보를 가져오지 못했습니다: %v", dest.Name, err)
+		} else {
+			view.Destination = &tileGroup{Name: dest.Name}
+			for _, occ := range occurrences {
+				for _, item := range slotsBetween(destWeather, occ.Start, occ.End) {
+					view.Destination.Tiles = append(view.Destination.Tiles, profileTile(item, occ.Window.Label))
+				}
+			}
+		}
+	}
+	renderFragment(w, "profile-weather", view)
+}
@@ -0,0 +1,390 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 규칙 평가 시 앞으로 몇 시간의 예보를 볼지
+const reminderWindow = 12 * time.Hour
+
+type reminderStore struct {
+	items    []models.Reminder
+	notified map[string]time.Time     // 이미 알림을 보낸 Reminder.ID와 보낸 시각
+	events   map[string]reminderEvent // 규칙 종류별로 이어지고 있는 날씨
+	mutex    sync.RWMutex
+}
+
+// reminderEvent는 한 번 잡힌 날씨(비, 한파 ...)가 처음 시작된 시각과 지금까지 알려진 끝 시각입니다.
+type reminderEvent struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+var activeReminders = &reminderStore{}
+
+func (s *reminderStore) set(items []models.Reminder) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.items = items
+}
+
+func notifiedRemindersPath() string { return dataPath("notified_reminders.json") }
+
+func reminderEventsPath() string { return dataPath("reminder_events.json") }
+
+// anchor는 규칙이 만든 알림에 흔들리지 않는 ID를 붙입니다.
+// 예보 구간이 매시간 밀리면서 이미 내리고 있는 비의 시작 시각이나 가장 추운 시각이 바뀌어도,
+// 앞서 잡힌 날씨와 이어지거나 겹치면 같은 날씨로 보고 처음 시작 시각으로 ID를 만듭니다.
+// items의 ID는 applyReminderRules가 만든 종류 키(시각 없음)여야 합니다.
+func (s *reminderStore) anchor(items []models.Reminder, now time.Time) []models.Reminder {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.events == nil {
+		s.events = make(map[string]reminderEvent)
+		if err := readJSONFile(reminderEventsPath(), &s.events); err != nil {
+			log.Printf("알림 날씨 기록 불러오기 실패: %v", err)
+		}
+	}
+	for key, event := range s.events {
+		if now.Sub(event.End) > 48*time.Hour {
+			delete(s.events, key)
+		}
+	}
+
+	result := make([]models.Reminder, 0, len(items))
+	for _, item := range items {
+		key := item.ID
+		event, ok := s.events[key]
+		if !ok || item.Start.After(event.End) {
+			event = reminderEvent{Start: item.Start, End: item.End}
+		}
+		if item.End.After(event.End) {
+			event.End = item.End
+		}
+		s.events[key] = event
+		item.ID = fmt.Sprintf("%s-%s", key, event.Start.Format("2006010215"))
+		// 캘린더 피드에서도 이미 시작된 날씨가 처음 시각 그대로 남도록 합니다.
+		item.Start = event.Start
+		result = append(result, item)
+	}
+	if err := writeJSONFile(reminderEventsPath(), s.events); err != nil {
+		log.Printf("알림 날씨 기록 저장 실패: %v", err)
+	}
+	return result
+}
+
+// 아직 알림을 보내지 않은 항목만 골라 보낸 것으로 기록합니다.
+// 재시작 후 같은 알림을 다시 보내지 않도록 기록은 파일에 저장하고, 이틀이 지난 기록은 지웁니다.
+func (s *reminderStore) markNew(items []models.Reminder, now time.Time) []models.Reminder {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.notified == nil {
+		s.notified = make(map[string]time.Time)
+		if err := readJSONFile(notifiedRemindersPath(), &s.notified); err != nil {
+			log.Printf("알림 기록 불러오기 실패: %v", err)
+		}
+	}
+	for id, sentAt := range s.notified {
+		if now.Sub(sentAt) > 48*time.Hour {
+			delete(s.notified, id)
+		}
+	}
+
+	var fresh []models.Reminder
+	for _, item := range items {
+		if _, sent := s.notified[item.ID]; sent {
+			continue
+		}
+		s.notified[item.ID] = now
+		fresh = append(fresh, item)
+	}
+	if err := writeJSONFile(notifiedRemindersPath(), s.notified); err != nil {
+		log.Printf("알림 기록 저장 실패: %v", err)
+	}
+	return fresh
+}
+
+func (s *reminderStore) list() []models.Reminder {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]models.Reminder(nil), s.items...)
+}
+
+// 심각도 비교용 순위 (값이 클수록 심각)
+func severityRank(severity string) int {
+	switch severity {
+	case models.SeverityCritical:
+		return 2
+	case models.SeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// reminderRule은 예보 구간을 보고 알림을 만들지 결정하는 규칙입니다.
+type reminderRule struct {
+	Kind  string
+	Check func(items []models.WeatherItem) (models.Reminder, bool)
+}
+
+var reminderRules = []reminderRule{
+	{Kind: "umbrella", Check: checkUmbrella},
+	{Kind: "cold", Check: checkCold},
+	{Kind: "heat", Check: checkHeat},
+	{Kind: "discomfort", Check: checkDiscomfort},
+	{Kind: "wind", Check: checkWind},
+}
+
+// [from, from+window) 구간의 예보만 시간순으로 골라냅니다.
+func forecastWindow(items []models.WeatherItem, from time.Time, window time.Duration) []models.WeatherItem {
+	var result []models.WeatherItem
+	for _, item := range items {
+		t, err := slotTime(item)
+		if err != nil {
+			continue
+		}
+		// 현재 시각이 포함된 칸도 포함하도록 1시간 여유를 둡니다.
+		if t.After(from.Add(-time.Hour)) && t.Before(from.Add(window)) {
+			result = append(result, item)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Date+result[i].Time < result[j].Date+result[j].Time
+	})
+	return result
+}
+
+func checkUmbrella(items []models.WeatherItem) (models.Reminder, bool) {
+	var start, end time.Time
+	maxPop := 0.0
+	for _, item := range items {
+		pop, _ := numericValue(item.Pop)
+		if item.Pty == "none" && pop < 60 {
+			continue
+		}
+		t, _ := slotTime(item)
+		if start.IsZero() {
+			start = t
+		}
+		end = t.Add(time.Hour)
+		if pop > maxPop {
+			maxPop = pop
+		}
+	}
+	if start.IsZero() {
+		return models.Reminder{}, false
+	}
+
+	severity := models.SeverityInfo
+	if maxPop >= 80 {
+		severity = models.SeverityWarning
+	}
+	return models.Reminder{
+		Kind:     "umbrella",
+		Severity: severity,
+		Title:    "우산 챙기세요",
+		Message:  fmt.Sprintf("%d시부터 비 소식이 있어요 (강수확률 최대 %.0f%%)", start.Hour(), maxPop),
+		Start:    start,
+		End:      end,
+	}, true
+}
+
+// 구간 내 최저/최고 기온과 그 시각을 찾습니다.
+func tempExtremes(items []models.WeatherItem) (minItem, maxItem *models.WeatherItem) {
+	return extremesBy(items, func(item models.WeatherItem) (float64, bool) { return numericValue(item.Tmp) })
+}
+
+// 구간 내 최저/최고 체감온도와 그 시각을 찾습니다.
+func feelsLikeExtremes(items []models.WeatherItem) (minItem, maxItem *models.WeatherItem) {
+	return extremesBy(items, feelsLikeValue)
+}
+
+func extremesBy(items []models.WeatherItem, value func(models.WeatherItem) (float64, bool)) (minItem, maxItem *models.WeatherItem) {
+	var low, high float64
+	for i := range items {
+		v, ok := value(items[i])
+		if !ok {
+			continue
+		}
+		if minItem == nil {
+			minItem, maxItem = &items[i], &items[i]
+			low, high = v, v
+			continue
+		}
+		if v < low {
+			minItem, low = &items[i], v
+		}
+		if v > high {
+			maxItem, high = &items[i], v
+		}
+	}
+	return minItem, maxItem
+}
+
+// "체감 -9℃ (기온 -4℃)". 체감온도가 기온과 같으면 기온만 씁니다.
+func feelsLikeText(item models.WeatherItem) string {
+	if item.FeelsLike == "" || item.FeelsLike == item.Tmp {
+		return "기온 " + item.Tmp
+	}
+	return fmt.Sprintf("체감 %s (기온 %s)", item.FeelsLike, item.Tmp)
+}
+
+// 한파/폭염은 체감온도로 판단합니다. 바람 부는 겨울 아침, 습한 여름 오후를 놓치지 않기 위해서입니다.
+func checkCold(items []models.WeatherItem) (models.Reminder, bool) {
+	minItem, _ := feelsLikeExtremes(items)
+	if minItem == nil {
+		return models.Reminder{}, false
+	}
+	tmp, _ := feelsLikeValue(*minItem)
+	if tmp > -5 {
+		return models.Reminder{}, false
+	}
+
+	severity := models.SeverityInfo
+	if tmp <= -12 {
+		severity = models.SeverityWarning
+	}
+	t, _ := slotTime(*minItem)
+	return models.Reminder{
+		Kind:     "cold",
+		Severity: severity,
+		Title:    "한파 대비",
+		Message:  fmt.Sprintf("%d시 %s, 따뜻하게 입으세요", t.Hour(), feelsLikeText(*minItem)),
+		Start:    t,
+		End:      t.Add(time.Hour),
+	}, true
+}
+
+func checkHeat(items []models.WeatherItem) (models.Reminder, bool) {
+	_, maxItem := feelsLikeExtremes(items)
+	if maxItem == nil {
+		return models.Reminder{}, false
+	}
+	tmp, _ := feelsLikeValue(*maxItem)
+	if tmp < 33 {
+		return models.Reminder{}, false
+	}
+
+	severity := models.SeverityWarning
+	if tmp >= 35 {
+		severity = models.SeverityCritical
+	}
+	t, _ := slotTime(*maxItem)
+	return models.Reminder{
+		Kind:     "heat",
+		Severity: severity,
+		Title:    "폭염 주의",
+		Message:  fmt.Sprintf("%d시 %s, 야외 활동을 줄이고 물을 자주 드세요", t.Hour(), feelsLikeText(*maxItem)),
+		Start:    t,
+		End:      t.Add(time.Hour),
+	}, true
+}
+
+// 불쾌지수 80 이상(매우 높음)인 시간대가 있으면 알려줍니다.
+func checkDiscomfort(items []models.WeatherItem) (models.Reminder, bool) {
+	_, maxItem := extremesBy(items, func(item models.WeatherItem) (float64, bool) { return numericValue(item.Discomfort) })
+	if maxItem == nil {
+		return models.Reminder{}, false
+	}
+	di, _ := numericValue(maxItem.Discomfort)
+	if di < 80 {
+		return models.Reminder{}, false
+	}
+	t, _ := slotTime(*maxItem)
+	return models.Reminder{
+		Kind:     "discomfort",
+		Severity: models.SeverityInfo,
+		Title:    "불쾌지수 매우 높음",
+		Message:  fmt.Sprintf("%d시 불쾌지수 %s, 통풍이 잘 되는 옷을 입고 실내 습도를 낮추세요", t.Hour(), maxItem.Discomfort),
+		Start:    t,
+		End:      t.Add(time.Hour),
+	}, true
+}
+
+// 예보 전체에 규칙을 적용해 알림 목록을 만듭니다.
+func evaluateReminders(allWeather []models.WeatherItem, now time.Time) []models.Reminder {
+	return applyReminderRules(forecastWindow(allWeather, now, reminderWindow), now, nil)
+}
+
+// 이미 골라낸 예보 구간에 규칙을 적용합니다. kinds가 비어 있지 않으면 그 종류의 규칙만 적용합니다.
+func applyReminderRules(window []models.WeatherItem, now time.Time, kinds []string) []models.Reminder {
+	var result []models.Reminder
+	for _, rule := range reminderRules {
+		if len(kinds) > 0 && !containsString(kinds, rule.Kind) {
+			continue
+		}
+		reminder, ok := rule.Check(window)
+		if !ok {
+			continue
+		}
+		// 시각은 reminderStore.anchor가 이어지는 날씨의 처음 시작 시각으로 붙입니다.
+		reminder.ID = rule.Kind
+		reminder.CreatedAt = now
+		result = append(result, reminder)
+	}
+	return result
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func reminderNotification(r models.Reminder) models.Notification {
+	return models.Notification{
+		Title:    r.Title,
+		Body:     r.Message,
+		Tag:      r.Kind,
+		URL:      "/",
+		Severity: r.Severity,
+	}
+}
+
+// 매시간 스케줄러가 호출하는 규칙 평가 작업
+func runReminderEvaluation() error {
+	allWeather, err := fetchAndCacheWeather()
+	if err != nil {
+		return err
+	}
+	now := time.Now().In(seoul)
+	reminders := append(activeReminders.anchor(evaluateReminders(allWeather, now), now), calendarReminders(now)...)
+	profileReminders := activeReminders.anchor(evaluateProfileReminders(now), now)
+	activeReminders.set(append(append([]models.Reminder(nil), reminders...), profileReminders...))
+
+	for _, r := range activeReminders.markNew(reminders, now) {
+		log.Printf("알림 [%s] %s: %s", r.Severity, r.Title, r.Message)
+		notify(reminderNotification(r))
+	}
+	for _, r := range activeReminders.markNew(profileReminders, now) {
+		log.Printf("알림 [%s] %s → %s: %s", r.Severity, r.Title, r.ProfileID, r.Message)
+		notifyRecipient(profileRecipient(r.ProfileID), reminderNotification(r))
+	}
+	// 알림이 바뀌었으니 Home Assistant 쪽 값도 바로 갱신합니다.
+	if err := runMQTTPublish(); err != nil {
+		log.Println(err)
+	}
+	return nil
+}
+
+// GetReminders는 현재 유효한 알림 목록을 JSON으로 반환합니다.
+func GetReminders(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	reminders := activeReminders.list()
+	if reminders == nil {
+		reminders = []models.Reminder{}
+	}
+	json.NewEncoder(w).Encode(reminders)
+}
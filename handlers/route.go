@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 이동 시간을 따로 지정하지 않았을 때 도착 시각 = 출발 시각 + 1시간으로 봅니다.
+const defaultTravelTime = time.Hour
+
+// routePlan은 통근 경로 예보를 만들기 위한 입력입니다.
+type routePlan struct {
+	Origin      models.Location
+	Destination models.Location
+	Depart      time.Time
+	Return      time.Time // zero면 편도
+	Travel      time.Duration
+}
+
+// 강수 형태의 나쁜 정도 (눈이 가장 나쁨)
+func precipSeverity(pty string) int {
+	switch pty {
+	case "🌨":
+		return 4
+	case "🌧(비/눈)":
+		return 3
+	case "🌧":
+		return 2
+	case "🌧(소나기)":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// t 시각이 포함된 예보 칸을 찾습니다.
+func slotAt(items []models.WeatherItem, t time.Time) *models.WeatherItem {
+	slots := slotsBetween(items, t, t.Add(time.Minute))
+	if len(slots) == 0 {
+		return nil
+	}
+	return &slots[0]
+}
+
+// buildRouteForecast는 출발지/도착지 예보를 가져와 각 지점의 예보와 경로 전체의 최악의 조건을 계산합니다.
+func buildRouteForecast(plan routePlan) (models.RouteForecast, error) {
+	originWeather, err := fetchAndCacheWeatherAt(plan.Origin)
+	if err != nil {
+		return models.RouteForecast{}, err
+	}
+	destWeather, err := fetchAndCacheWeatherAt(plan.Destination)
+	if err != nil {
+		return models.RouteForecast{}, err
+	}
+
+	legs := []models.RouteLeg{
+		{Label: "출발", Location: plan.Origin, Time: plan.Depart, Weather: slotAt(originWeather, plan.Depart)},
+		{Label: "도착", Location: plan.Destination, Time: plan.Depart.Add(plan.Travel), Weather: slotAt(destWeather, plan.Depart.Add(plan.Travel))},
+	}
+	if !plan.Return.IsZero() {
+		legs = append(legs,
+			models.RouteLeg{Label: "귀가 출발", Location: plan.Destination, Time: plan.Return, Weather: slotAt(destWeather, plan.Return)},
+			models.RouteLeg{Label: "귀가 도착", Location: plan.Origin, Time: plan.Return.Add(plan.Travel), Weather: slotAt(originWeather, plan.Return.Add(plan.Travel))},
+		)
+	}
+
+	// 최악의 조건은 네 시각만이 아니라 가는 길, 도착지에 머무는 동안, 돌아오는 길의 모든 예보 칸으로 계산합니다.
+	// 이동 중에는 출발지와 도착지 중 어느 쪽 날씨를 만날지 모르므로 두 곳을 모두 봅니다.
+	arrive := plan.Depart.Add(plan.Travel)
+	var spans [][]models.WeatherItem
+	if plan.Return.IsZero() {
+		spans = append(spans,
+			slotsBetween(originWeather, plan.Depart, arrive),
+			slotsBetween(destWeather, plan.Depart, arrive),
+		)
+	} else {
+		home := plan.Return.Add(plan.Travel)
+		spans = append(spans,
+			slotsBetween(originWeather, plan.Depart, arrive),
+			slotsBetween(destWeather, plan.Depart, home),
+			slotsBetween(originWeather, plan.Return, home),
+		)
+	}
+
+	forecast := models.RouteForecast{Legs: legs}
+	for _, span := range spans {
+		for _, item := range span {
+			if pop, ok := numericValue(item.Pop); ok && pop > forecast.MaxPop {
+				forecast.MaxPop = pop
+			}
+			if precipSeverity(item.Pty) > precipSeverity(forecast.PrecipKind) {
+				forecast.PrecipKind = item.Pty
+			}
+			if tmp, ok := numericValue(item.Tmp); ok {
+				if forecast.MinTemp == nil || tmp < *forecast.MinTemp {
+					low := tmp
+					forecast.MinTemp = &low
+				}
+				if forecast.MaxTemp == nil || tmp > *forecast.MaxTemp {
+					high := tmp
+					forecast.MaxTemp = &high
+				}
+			}
+		}
+	}
+	return forecast, nil
+}
+
+// "61,125" 형태의 격자 좌표를 파싱합니다.
+func parseGrid(s, name string) (models.Location, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 2 {
+		return models.Location{}, fmt.Errorf("%s는 nx,ny 형식이어야 합니다", name)
+	}
+	nx, err1 := strconv.Atoi(strings.TrimSpace(parts[0]))
+	ny, err2 := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err1 != nil || err2 != nil {
+		return models.Location{}, fmt.Errorf("%s는 nx,ny 형식이어야 합니다", name)
+	}
+	// 격자마다 기상청 API를 새로 부르고 캐시가 생기므로 예보가 없는 좌표는 받지 않습니다.
+	if !validGrid(nx, ny) {
+		return models.Location{}, fmt.Errorf("%s는 nx 1~%d, ny 1~%d 범위여야 합니다", name, gridMaxX, gridMaxY)
+	}
+	return models.Location{Name: name, Nx: nx, Ny: ny}, nil
+}
+
+// "07:30"이 가리키는 가장 가까운 앞으로의 시각 (after 이후 처음)
+func nextClock(clock string, after time.Time) (time.Time, error) {
+	minutes, err := parseClock(clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	day := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, seoul)
+	t := day.Add(time.Duration(minutes) * time.Minute)
+	if t.Before(after) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// 프로필의 출발지/도착지와 앞으로 24시간 안의 첫 출발 통근, 그 뒤의 첫 귀가 통근으로 계획을 만듭니다.
+// 시간순이 아니라 통근 시간대의 방향으로 고르므로, 출근 시간이 지난 뒤에는 내일 출근과 내일 퇴근으로 계획합니다.
+// 귀가 시간대가 없으면 귀가 예보는 비워 둡니다.
+func planFromProfile(p models.Profile, now time.Time) (routePlan, error) {
+	dest, ok := profileDestination(p)
+	if !ok {
+		return routePlan{}, fmt.Errorf("%s님의 도착지가 설정되지 않았습니다", p.Name)
+	}
+	origin := profileOrigin(p)
+	from := now.Add(-time.Hour)
+	// 출발은 24시간 안에서 찾고, 귀가는 그 출발 뒤의 시간대를 찾아야 하므로 하루 더 봅니다.
+	occurrences := commuteOccurrences(p, from, 48*time.Hour)
+
+	plan := routePlan{Origin: origin, Destination: dest, Travel: defaultTravelTime}
+	for _, occ := range occurrences {
+		if occ.Start.Before(from.Add(24*time.Hour)) && commuteDirection(occ.Window) == models.CommuteOutbound {
+			plan.Depart = occ.Start
+			break
+		}
+	}
+	if plan.Depart.IsZero() {
+		return routePlan{}, fmt.Errorf("%s님의 출발 통근 시간대가 없습니다", p.Name)
+	}
+	for _, occ := range occurrences {
+		if occ.Start.After(plan.Depart) && commuteDirection(occ.Window) == models.CommuteReturn {
+			plan.Return = occ.Start
+			break
+		}
+	}
+	return plan, nil
+}
+
+// 쿼리 파라미터로 계획을 만듭니다.
+// profile=ID 또는 origin=nx,ny&dest=nx,ny&depart=07:30[&return=18:00][&travel=40]
+func planFromRequest(r *http.Request, now time.Time) (routePlan, error) {
+	q := r.URL.Query()
+	if id := q.Get("profile"); id != "" {
+		p, ok := profiles.get(id)
+		if !ok {
+			return routePlan{}, fmt.Errorf("프로필을 찾을 수 없습니다")
+		}
+		return planFromProfile(p, now)
+	}
+
+	plan := routePlan{Origin: homeLocation(), Travel: defaultTravelTime}
+	var err error
+	if origin := q.Get("origin"); origin != "" {
+		if plan.Origin, err = parseGrid(origin, "출발지"); err != nil {
+			return routePlan{}, err
+		}
+	}
+	if plan.Destination, err = parseGrid(q.Get("dest"), "도착지"); err != nil {
+		return routePlan{}, err
+	}
+	if plan.Depart, err = nextClock(q.Get("depart"), now.Add(-time.Hour)); err != nil {
+		return routePlan{}, err
+	}
+	if ret := q.Get("return"); ret != "" {
+		if plan.Return, err = nextClock(ret, plan.Depart); err != nil {
+			return routePlan{}, err
+		}
+	}
+	if travel := q.Get("travel"); travel != "" {
+		minutes, err := strconv.Atoi(travel)
+		if err != nil || minutes <= 0 {
+			return routePlan{}, fmt.Errorf("travel은 분 단위 양수여야 합니다")
+		}
+		plan.Travel = time.Duration(minutes) * time.Minute
+	}
+	return plan, nil
+}
+
+// GetRouteForecast는 출발지와 도착지의 통근 시각 예보를 합친 "경로 예보" 조각을 반환합니다.
+func GetRouteForecast(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	plan, err := planFromRequest(r, time.Now().In(seoul))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	forecast, err := buildRouteForecast(plan)
+	if err != nil {
+		http.Error(w, "날씨 정보를 가져올 수 없습니다.", http.StatusInternalServerError)
+		return
+	}
+
+	type legView struct {
+		Label, Place, Time string
+		Tile               *tileView
+	}
+	var legs []legView
+	for _, leg := range forecast.Legs {
+		view := legView{Label: leg.Label, Place: leg.Location.Name, Time: leg.Time.Format("15:04")}
+		if leg.Weather != nil {
+			tile := newTile(*leg.Weather)
+			view.Tile = &tile
+		}
+		legs = append(legs, view)
+	}
+
+	var worst []string
+	if forecast.PrecipKind != "" && forecast.PrecipKind != "none" {
+		worst = append(worst, fmt.Sprintf("%s 예보", precipWord(forecast.PrecipKind)))
+	}
+	worst = append(worst, fmt.Sprintf("강수확률 최대 %.0f%%", forecast.MaxPop))
+	if forecast.MinTemp != nil {
+		worst = append(worst, fmt.Sprintf("기온 %.0f~%.0f℃", *forecast.MinTemp, *forecast.MaxTemp))
+	}
+	renderFragment(w, "route-forecast", struct {
+		Legs  []legView
+		Worst []string
+	}{legs, worst})
+}
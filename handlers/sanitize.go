@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"html"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Naver 검색 API의 제목/요약은 <b> 같은 태그와 HTML 엔티티가 섞인 HTML 조각입니다.
+// 화면과 알림에는 태그를 모두 걷어낸 순수한 글만 쓰고, 이스케이프는 템플릿에 맡깁니다.
+
+// 설정이 없을 때 요약을 자르는 길이 (글자 수)
+const defaultNewsDescriptionLength = 120
+
+// newsDescriptionLength는 NEWS_DESCRIPTION_LENGTH(글자 수)입니다. 0이면 자르지 않습니다.
+func newsDescriptionLength() int {
+	if n, err := strconv.Atoi(os.Getenv("NEWS_DESCRIPTION_LENGTH")); err == nil && n >= 0 {
+		return n
+	}
+	return defaultNewsDescriptionLength
+}
+
+// cleanNewsText는 태그를 걷어내고, 모든 HTML5 엔티티를 풀고, 공백을 한 칸으로 모읍니다.
+func cleanNewsText(s string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(stripMarkup(s))), " ")
+}
+
+// 내용까지 버리는 태그
+var rawTextTags = map[string]bool{"script": true, "style": true, "title": true, "textarea": true}
+
+// 앞뒤 글자가 붙지 않도록 공백으로 바꾸는 태그
+var blockTags = map[string]bool{
+	"br": true, "p": true, "div": true, "li": true, "ul": true, "ol": true, "tr": true, "td": true, "th": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "blockquote": true, "hr": true,
+}
+
+// stripMarkup은 태그, 주석, 스크립트/스타일 내용을 지웁니다. "a < b"처럼 태그가 아닌 <는 그대로 둡니다.
+// CDATA 구간은 안의 글을 태그로 읽지 않고 그대로 남깁니다.
+func stripMarkup(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		if rest[0] != '<' {
+			next := strings.IndexByte(rest, '<')
+			if next < 0 {
+				next = len(rest)
+			}
+			b.WriteString(rest[:next])
+			i += next
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				return b.String()
+			}
+			i += 4 + end + 3
+		case strings.HasPrefix(rest, "<![CDATA["):
+			end := strings.Index(rest[9:], "]]>")
+			if end < 0 {
+				return b.String()
+			}
+			b.WriteString(rest[9 : 9+end])
+			i += 9 + end + 3
+		case isTagStart(rest):
+			end := tagEnd(rest)
+			if end < 0 {
+				// 닫히지 않은 태그 뒤는 버립니다.
+				return b.String()
+			}
+			name, closing := tagName(rest)
+			i += end + 1
+			if blockTags[name] {
+				b.WriteByte(' ')
+			}
+			if rawTextTags[name] && !closing {
+				closeAt := strings.Index(strings.ToLower(s[i:]), "</"+name)
+				if closeAt < 0 {
+					return b.String()
+				}
+				i += closeAt
+			}
+		default:
+			b.WriteByte('<')
+			i++
+		}
+	}
+	return b.String()
+}
+
+// <a, </a, <!DOCTYPE, <?xml 처럼 태그로 읽히는지
+func isTagStart(s string) bool {
+	if len(s) < 2 {
+		return false
+	}
+	c := s[1]
+	if c == '/' && len(s) > 2 {
+		c = s[2]
+	} else if c == '!' || c == '?' {
+		return true
+	}
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// tagEnd는 태그를 닫는 >의 위치입니다. 따옴표로 감싼 속성값 안의 >는 건너뜁니다.
+func tagEnd(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return i
+		}
+	}
+	return -1
+}
+
+// tagName은 소문자 태그 이름과 닫는 태그인지를 돌려줍니다.
+func tagName(s string) (string, bool) {
+	s = s[1:]
+	closing := strings.HasPrefix(s, "/")
+	s = strings.TrimPrefix(s, "/")
+	end := strings.IndexFunc(s, func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+	if end < 0 {
+		end = len(s)
+	}
+	return strings.ToLower(s[:end]), closing
+}
+
+// truncateGraphemes는 글자(사용자가 한 글자로 보는 단위) limit개까지 남기고 "…"를 붙입니다.
+// 결합 문자, 이모지 조합, 국기, 첫가끝 한글이 중간에서 잘리지 않습니다. limit이 0이면 자르지 않습니다.
+func truncateGraphemes(s string, limit int) string {
+	if limit <= 0 || utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	count, regional := 0, 0
+	prev := rune(-1)
+	for i, r := range s {
+		if prev < 0 || !joinsPrevious(prev, r, regional) {
+			count++
+			if count > limit {
+				return strings.TrimRightFunc(s[:i], unicode.IsSpace) + "…"
+			}
+		}
+		if isRegionalIndicator(r) {
+			regional++
+		} else {
+			regional = 0
+		}
+		prev = r
+	}
+	return s
+}
+
+// joinsPrevious는 r이 앞 글자 prev에 붙어 한 글자가 되는지 알려줍니다. (UAX #29의 주요 규칙)
+// regional은 prev까지 이어진 국기용 지역 표시 문자의 개수입니다.
+func joinsPrevious(prev, r rune, regional int) bool {
+	switch {
+	case prev == '\r' && r == '\n':
+		return true
+	case isGraphemeExtend(r) || r == '\u200d' || unicode.Is(unicode.Mc, r):
+		return true
+	case prev == '\u200d' && isPictographic(r):
+		return true
+	case isRegionalIndicator(prev) && isRegionalIndicator(r):
+		return regional%2 == 1
+	}
+	return joinsHangul(prev, r)
+}
+
+func isGraphemeExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me) ||
+		(r >= 0xFE00 && r <= 0xFE0F) || // 이체자 선택자
+		(r >= 0x1F3FB && r <= 0x1F3FF) || // 피부색 조정
+		(r >= 0xE0020 && r <= 0xE007F) || // 태그 문자
+		(r >= 0xE0100 && r <= 0xE01EF)
+}
+
+func isPictographic(r rune) bool {
+	return (r >= 0x1F000 && r <= 0x1FAFF) || (r >= 0x2600 && r <= 0x27BF) || r == 0x2764
+}
+
+func isRegionalIndicator(r rune) bool { return r >= 0x1F1E6 && r <= 0x1F1FF }
+
+// 첫가끝 한글 자모 (초성 L, 중성 V, 종성 T)와 완성형 음절(LV, LVT)의 결합 규칙
+func joinsHangul(prev, r rune) bool {
+	kind := func(r rune) string {
+		switch {
+		case (r >= 0x1100 && r <= 0x115F) || (r >= 0xA960 && r <= 0xA97C):
+			return "L"
+		case (r >= 0x1160 && r <= 0x11A7) || (r >= 0xD7B0 && r <= 0xD7C6):
+			return "V"
+		case (r >= 0x11A8 && r <= 0x11FF) || (r >= 0xD7CB && r <= 0xD7FB):
+			return "T"
+		case r >= 0xAC00 && r <= 0xD7A3:
+			if (r-0xAC00)%28 == 0 {
+				return "LV"
+			}
+			return "LVT"
+		}
+		return ""
+	}
+	switch p, c := kind(prev), kind(r); p {
+	case "L":
+		return c == "L" || c == "V" || c == "LV" || c == "LVT"
+	case "LV", "V":
+		return c == "V" || c == "T"
+	case "LVT", "T":
+		return c == "T"
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import "testing"
+
+func TestCleanNewsText(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"강조 태그", "<b>한국은행</b> 기준금리 동결", "한국은행 기준금리 동결"},
+		{"이름 있는 엔티티", "&quot;첫눈&quot; &amp; 한파 &lt;속보&gt;", `"첫눈" & 한파 <속보>`},
+		{"숫자 엔티티", "&#39;PSG&#x27; &#54620;&#44397;", "'PSG' 한국"},
+		{"HTML5 엔티티", "3&middot;1절 &hellip; &nbsp;끝", "3·1절 … 끝"},
+		{"세미콜론 없는 엔티티", "AT&T &amp 삼성", "AT&T & 삼성"},
+		{"속성 안의 >", `<a href="x>y" title='a>b'>링크</a> 뒤`, "링크 뒤"},
+		{"닫히지 않은 태그", "앞부분 <b class=\"x", "앞부분"},
+		{"닫히지 않은 주석", "앞 <!-- 주석", "앞"},
+		{"태그가 아닌 <", "a < b, 3<5", "a < b, 3<5"},
+		{"블록 태그는 공백", "첫 줄<br>둘째 줄<p>셋째</p>", "첫 줄 둘째 줄 셋째"},
+		{"인라인 태그는 붙임", "기준<b>금리</b>", "기준금리"},
+		{"주석", "앞<!-- <b>숨김</b> -->뒤", "앞뒤"},
+		{"script 내용", `앞<script>alert("<b>x</b>")</script>뒤`, "앞뒤"},
+		{"대문자 SCRIPT", "앞<SCRIPT type=text/javascript>var a = 1 < 2;</SCRIPT>뒤", "앞뒤"},
+		{"style 내용", "<style>p { color: red }</style>본문", "본문"},
+		{"닫히지 않은 script", "앞<script>alert(1)", "앞"},
+		{"CDATA", "<![CDATA[금리 > 3%]]> 동결", "금리 > 3% 동결"},
+		{"CDATA 안의 태그 문자", "<![CDATA[<b>굵게</b>]]>", "<b>굵게</b>"},
+		{"닫히지 않은 CDATA", "앞<![CDATA[뒤", "앞"},
+		{"DOCTYPE과 처리 명령", "<!DOCTYPE html><?xml version=\"1.0\"?>본문", "본문"},
+		{"공백 정리", "  여러\n\t줄   공백  ", "여러 줄 공백"},
+	}
+	for _, tt := range tests {
+		if got := cleanNewsText(tt.in); got != tt.want {
+			t.Errorf("%s: cleanNewsText(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateGraphemes(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		limit int
+		want  string
+	}{
+		{"짧으면 그대로", "날씨", 5, "날씨"},
+		{"0이면 자르지 않음", "오늘 날씨 맑음", 0, "오늘 날씨 맑음"},
+		{"완성형 한글", "오늘 날씨 맑음", 4, "오늘 날…"},
+		{"잘린 자리의 공백", "오늘 날씨", 3, "오늘…"},
+		{"결합 문자", "café au lait", 4, "café…"},
+		{"첫가끝 한글", "\u1112\u1161\u11ab\u1100\u1173\u11af 날씨", 2, "\u1112\u1161\u11ab\u1100\u1173\u11af…"},
+		{"첫가끝 한글 중간", "\u1112\u1161\u11ab\u1100\u1173\u11af", 1, "\u1112\u1161\u11ab…"},
+		{"받침 없는 첫가끝", "\u1100\u1161\u1102\u1161", 1, "\u1100\u1161…"},
+		{"완성형 + 종성 자모", "\uac00\u11a8\ub098", 1, "\uac00\u11a8…"},
+		{"종성 뒤 초성은 새 글자", "\uac01\u1100\u1161", 1, "\uac01…"},
+		{"ZWJ 가족 이모지", "👨‍👩‍👧‍👦 가족 나들이", 1, "👨‍👩‍👧‍👦…"},
+		{"피부색 조정", "👍🏽👍🏽👍🏽", 2, "👍🏽👍🏽…"},
+		{"이체자 선택자", "☀️ 맑음", 1, "☀️…"},
+		{"국기", "🇰🇷🇯🇵🇺🇸", 2, "🇰🇷🇯🇵…"},
+		{"국기 하나", "🇰🇷🇯🇵", 1, "🇰🇷…"},
+		{"태그 문자 국기", "🏴\U000E0067\U000E0062\U000E0065\U000E006E\U000E0067\U000E007F 잉글랜드", 1, "🏴\U000E0067\U000E0062\U000E0065\U000E006E\U000E0067\U000E007F…"},
+		{"CRLF는 한 글자", "a\r\nbc", 3, "a\r\nb…"},
+	}
+	for _, tt := range tests {
+		if got := truncateGraphemes(tt.in, tt.limit); got != tt.want {
+			t.Errorf("%s: truncateGraphemes(%q, %d) = %q, want %q", tt.name, tt.in, tt.limit, got, tt.want)
+		}
+	}
+}
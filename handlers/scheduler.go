@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 예약 작업은 서버의 로컬 타임존과 상관없이 항상 서울 시각으로 계산합니다.
+var seoul = loadSeoulLocation()
+
+func loadSeoulLocation() *time.Location {
+	loc, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		// tzdata가 없는 경량 OS에서도 동작하도록 고정 오프셋으로 대체합니다.
+		log.Println("Warning: Asia/Seoul 타임존을 찾을 수 없어 UTC+9를 사용합니다:", err)
+		return time.FixedZone("KST", 9*60*60)
+	}
+	return loc
+}
+
+// Job은 cron 표현식에 따라 주기적으로 실행되는 작업입니다.
+type Job struct {
+	Name        string
+	Description string
+	Spec        string
+	// 서버가 꺼져 있어 놓친 실행을, 예정 시각으로부터 이 시간 안에 재시작했다면 즉시 실행합니다.
+	// 0이면 놓친 실행은 건너뜁니다.
+	CatchUpWithin time.Duration
+	Run           func() error
+
+	schedule  *CronSchedule
+	lastRun   time.Time
+	nextRun   time.Time
+	lastError string
+	running   bool
+}
+
+type Scheduler struct {
+	jobs    []*Job
+	mutex   sync.Mutex
+	wake    chan struct{}
+	started bool
+}
+
+// 스케줄러 상태 파일에 저장되는 작업별 마지막 실행 시각
+type schedulerState struct {
+	LastRun map[string]time.Time `json:"lastRun"`
+}
+
+var scheduler = &Scheduler{wake: make(chan struct{}, 1)}
+
+func schedulerStatePath() string {
+	return dataPath("scheduler_state.json")
+}
+
+// AddJob은 작업을 등록합니다. cron 표현식이 잘못되었으면 에러를 반환합니다.
+func (s *Scheduler) AddJob(job *Job) error {
+	schedule, err := ParseCron(job.Spec, seoul)
+	if err != nil {
+		return fmt.Errorf("작업 %s 등록 실패: %v", job.Name, err)
+	}
+	// 2월 31일처럼 일치하는 날이 없는 표현식은 실행될 일이 없으므로 받지 않습니다.
+	if schedule.Next(time.Now()).IsZero() {
+		return fmt.Errorf("작업 %s 등록 실패: %q와 일치하는 실행 시각이 없습니다", job.Name, job.Spec)
+	}
+	job.schedule = schedule
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.jobs = append(s.jobs, job)
+	// 이미 돌고 있는 스케줄러에 추가된 작업은 바로 다음 실행 시각을 계산해 루프를 깨웁니다.
+	if s.started {
+		job.nextRun = schedule.Next(time.Now())
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) loadState() {
+	var state schedulerState
+	if err := readJSONFile(schedulerStatePath(), &state); err != nil {
+		log.Printf("스케줄러 상태 불러오기 실패: %v", err)
+		return
+	}
+	for _, job := range s.jobs {
+		if t, ok := state.LastRun[job.Name]; ok {
+			job.lastRun = t
+		}
+	}
+}
+
+// 놓친 실행을 따라잡는 작업(CatchUpWithin > 0)만 마지막 실행 시각을 저장합니다. 호출 시 s.mutex를 잡고 있어야 합니다.
+func (s *Scheduler) saveState() {
+	state := schedulerState{LastRun: make(map[string]time.Time)}
+	for _, job := range s.jobs {
+		if job.CatchUpWithin > 0 && !job.lastRun.IsZero() {
+			state.LastRun[job.Name] = job.lastRun
+		}
+	}
+	if err := writeJSONFile(schedulerStatePath(), state); err != nil {
+		log.Printf("스케줄러 상태 저장 실패: %v", err)
+	}
+}
+
+// Start는 저장된 실행 기록을 불러와 놓친 작업을 처리한 뒤 백그라운드 루프를 시작합니다.
+func (s *Scheduler) Start() {
+	s.mutex.Lock()
+	s.started = true
+	s.loadState()
+	now := time.Now().In(seoul)
+	for _, job := range s.jobs {
+		job.nextRun = job.schedule.Next(now)
+		if job.nextRun.IsZero() {
+			log.Printf("작업 %s: 다음 실행 시각이 없어 실행하지 않습니다", job.Name)
+			continue
+		}
+		if job.lastRun.IsZero() || job.CatchUpWithin == 0 {
+			continue
+		}
+		// 마지막 실행 이후 예정되어 있던 가장 최근 실행을 찾습니다.
+		var missed time.Time
+		for t := job.schedule.Next(job.lastRun); !t.IsZero() && !t.After(now); t = job.schedule.Next(t) {
+			missed = t
+		}
+		if !missed.IsZero() && now.Sub(missed) <= job.CatchUpWithin {
+			log.Printf("놓친 작업 실행: %s (예정 시각 %v)", job.Name, missed)
+			s.launch(job, now)
+		}
+	}
+	s.mutex.Unlock()
+
+	go s.loop()
+}
+
+func (s *Scheduler) loop() {
+	for {
+		s.mutex.Lock()
+		var earliest time.Time
+		for _, job := range s.jobs {
+			// 다음 실행 시각이 없는(zero) 작업은 건너뜁니다. 그렇지 않으면 타이머가 곧바로 울려 쉬지 않고 돕니다.
+			if job.nextRun.IsZero() {
+				continue
+			}
+			if earliest.IsZero() || job.nextRun.Before(earliest) {
+				earliest = job.nextRun
+			}
+		}
+		s.mutex.Unlock()
+
+		wait := time.Minute
+		if !earliest.IsZero() {
+			wait = time.Until(earliest)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-s.wake:
+			timer.Stop()
+		}
+
+		s.mutex.Lock()
+		now := time.Now().In(seoul)
+		for _, job := range s.jobs {
+			if !job.nextRun.IsZero() && !job.nextRun.After(now) {
+				s.launch(job, now)
+				job.nextRun = job.schedule.Next(now)
+			}
+		}
+		s.mutex.Unlock()
+	}
+}
+
+// 작업을 별도 고루틴에서 실행합니다. 호출 시 s.mutex를 잡고 있어야 합니다.
+func (s *Scheduler) launch(job *Job, now time.Time) {
+	if job.running {
+		log.Printf("작업 %s이(가) 아직 실행 중이라 이번 실행은 건너뜁니다", job.Name)
+		return
+	}
+	job.running = true
+	job.lastRun = now
+	// 매분 도는 작업 때문에 상태 파일을 매번 다시 쓰지 않도록, 재시작 후 따라잡기에 필요한 작업만 저장합니다.
+	if job.CatchUpWithin > 0 {
+		s.saveState()
+	}
+
+	go func() {
+		start := time.Now()
+		err := job.Run()
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		job.running = false
+		job.lastError = ""
+		if err != nil {
+			job.lastError = err.Error()
+			log.Printf("작업 %s 실패: %v", job.Name, err)
+			return
+		}
+		log.Printf("작업 %s 완료 (%v)", job.Name, time.Since(start))
+	}()
+}
+
+// Jobs는 등록된 작업 목록을 다음 실행 시각 순으로 반환합니다.
+func (s *Scheduler) Jobs() []models.JobInfo {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	result := make([]models.JobInfo, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		info := models.JobInfo{
+			Name:        job.Name,
+			Description: job.Description,
+			Spec:        job.Spec,
+			NextRun:     job.nextRun,
+			LastError:   job.lastError,
+			Running:     job.running,
+		}
+		if !job.lastRun.IsZero() {
+			lastRun := job.lastRun
+			info.LastRun = &lastRun
+		}
+		result = append(result, info)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].NextRun.Before(result[j].NextRun)
+	})
+	return result
+}
+
+// StartScheduler는 기본 작업(아침 브리핑, 저녁 내일 요약, 매시간 알림 평가, 관측 저장, 이력 정리, 보류 알림 발송)을 등록하고 스케줄러를 시작합니다.
+func StartScheduler() {
+	defaultJobs := []*Job{
+		{
+			Name:          "morning-briefing",
+			Description:   "평일 아침 오늘 날씨 브리핑",
+			Spec:          "30 6 * * 1-5",
+			CatchUpWithin: 2 * time.Hour,
+			Run:           runMorningBriefing,
+		},
+		{
+			Name:          "evening-summary",
+			Description:   "저녁 내일 날씨 요약",
+			Spec:          "0 21 * * *",
+			CatchUpWithin: 2 * time.Hour,
+			Run:           runEveningSummary,
+		},
+		{
+			Name:          "reminder-evaluation",
+			Description:   "매시간 알림 규칙 평가",
+			Spec:          "0 * * * *",
+			CatchUpWithin: time.Hour,
+			Run:           runReminderEvaluation,
+		},
+		{
+			Name:        "nowcast-observation",
+			Description: "매시 초단기실황 관측값 저장",
+			Spec:        "45 * * * *",
+			Run:         runNowcastObservation,
+		},
+		{
+			Name:        "history-retention",
+			Description: "보관 기간이 지난 예보/관측 이력 삭제",
+			Spec:        "30 3 * * *",
+			Run:         runHistoryRetention,
+		},
+		{
+			Name:        "notification-flush",
+			Description: "방해 금지 시간이 끝난 알림과 요약 발송",
+			Spec:        "* * * * *",
+			Run:         runNotificationFlush,
+		},
+	}
+	for _, job := range defaultJobs {
+		if err := scheduler.AddJob(job); err != nil {
+			log.Println(err)
+		}
+	}
+	scheduler.Start()
+	log.Printf("스케줄러 시작: 작업 %d개", len(defaultJobs))
+}
+
+// GetJobs는 예약 작업 목록과 마지막/다음 실행 시각을 JSON으로 반환합니다.
+func GetJobs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scheduler.Jobs())
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// 상태 파일(스케줄러 실행 기록 등)을 저장할 디렉토리를 반환합니다.
+// DATA_DIR 환경변수가 없으면 작업 디렉토리 아래 data/를 사용합니다.
+func dataDir() string {
+	if dir := os.Getenv("DATA_DIR"); dir != "" {
+		return dir
+	}
+	return "data"
+}
+
+func dataPath(name string) string {
+	return filepath.Join(dataDir(), name)
+}
+
+// JSON 파일을 읽어 v에 채웁니다. 파일이 없으면 v를 건드리지 않고 nil을 반환합니다.
+func readJSONFile(path string, v interface{}) error {
+	body, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s 읽기 실패: %v", path, err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("%s 파싱 실패: %v", path, err)
+	}
+	return nil
+}
+
+// v를 JSON으로 저장합니다. 쓰는 도중 전원이 나가도 기존 파일이 깨지지 않도록
+// 임시 파일에 먼저 쓴 뒤 rename 합니다.
+func writeJSONFile(path string, v interface{}) error {
+	body, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("%s 직렬화 실패: %v", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("%s 디렉토리 생성 실패: %v", path, err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, body, 0o644); err != nil {
+		return fmt.Errorf("%s 쓰기 실패: %v", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("%s 저장 실패: %v", path, err)
+	}
+	return nil
+}
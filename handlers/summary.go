@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// "오후 3시", "새벽 2시"처럼 시각을 읽기 쉽게 바꿉니다.
+func hourPhrase(hour int) string {
+	switch {
+	case hour < 6:
+		return fmt.Sprintf("새벽 %d시", hour)
+	case hour < 12:
+		return fmt.Sprintf("오전 %d시", hour)
+	case hour == 12:
+		return "낮 12시"
+	case hour < 18:
+		return fmt.Sprintf("오후 %d시", hour-12)
+	case hour < 21:
+		return fmt.Sprintf("저녁 %d시", hour-12)
+	default:
+		return fmt.Sprintf("밤 %d시", hour-12)
+	}
+}
+
+// parseCategory로 바꾼 강수 형태 아이콘을 다시 말로 바꿉니다.
+func precipWord(pty string) string {
+	switch pty {
+	case "🌨":
+		return "눈"
+	case "🌧(비/눈)":
+		return "비나 눈"
+	case "🌧(소나기)":
+		return "소나기"
+	default:
+		return "비"
+	}
+}
+
+func skyWord(sky string) string {
+	switch sky {
+	case "🌤":
+		return "맑음"
+	case "🌥":
+		return "구름많음"
+	case "☁":
+		return "흐림"
+	default:
+		return ""
+	}
+}
+
+// 오전(~12시)과 오후의 대표 하늘 상태를 비교해 흐름을 만듭니다.
+func skyTrend(items []models.WeatherItem) string {
+	count := [2]map[string]int{{}, {}}
+	for _, item := range items {
+		word := skyWord(item.Sky)
+		if word == "" {
+			continue
+		}
+		half := 0
+		if hour, _ := strconv.Atoi(item.Time[:2]); hour >= 12 {
+			half = 1
+		}
+		count[half][word]++
+	}
+	dominant := func(c map[string]int) string {
+		best, bestCount := "", 0
+		for _, word := range []string{"맑음", "구름많음", "흐림"} {
+			if c[word] > bestCount {
+				best, bestCount = word, c[word]
+			}
+		}
+		return best
+	}
+	am, pm := dominant(count[0]), dominant(count[1])
+	if am == "" {
+		am = pm
+	}
+	if pm == "" {
+		pm = am
+	}
+	switch {
+	case am == "":
+		return ""
+	case am == pm:
+		return skySteady[pm]
+	case skyRank(pm) > skyRank(am):
+		return "오후부터 " + skyWorsening[pm]
+	default:
+		return "오후부터 " + skyImproving[pm]
+	}
+}
+
+var (
+	skySteady    = map[string]string{"맑음": "대체로 맑음", "구름많음": "구름 많음", "흐림": "흐림"}
+	skyWorsening = map[string]string{"구름많음": "구름 많아짐", "흐림": "흐려짐"}
+	skyImproving = map[string]string{"맑음": "맑아짐", "구름많음": "구름 걷힘"}
+)
+
+func skyRank(word string) int {
+	switch word {
+	case "구름많음":
+		return 1
+	case "흐림":
+		return 2
+	default:
+		return 0
+	}
+}
+
+// consecutiveSlots는 두 예보 칸이 한 시간 간격으로 이어지는지 봅니다.
+func consecutiveSlots(a, b models.WeatherItem) bool {
+	ta, errA := slotTime(a)
+	tb, errB := slotTime(b)
+	return errA == nil && errB == nil && tb.Sub(ta) == time.Hour
+}
+
+// summarizeDay는 하루치 예보(시간순)를 한 문장으로 요약합니다.
+// dayLabel은 "오늘", "내일"처럼 문장 앞에 붙일 말입니다.
+func summarizeDay(items []models.WeatherItem, date, dayLabel string) models.ForecastSummary {
+	summary := models.ForecastSummary{Date: date}
+	if len(items) == 0 {
+		summary.Text = fmt.Sprintf("%s 예보 정보가 없습니다", dayLabel)
+		return summary
+	}
+
+	var parts []string
+
+	// 1. 강수 시간대. 9시와 21시에 따로 오는 비를 "9~22시 비"로 뭉치지 않도록 이어지는 시간대마다 나눕니다.
+	type wetRun struct{ first, last int }
+	var runs []wetRun
+	worstPty := ""
+	for i, item := range items {
+		if item.Pty != "none" && item.Pty != "" {
+			if n := len(runs); n > 0 && runs[n-1].last == i-1 && consecutiveSlots(items[i-1], item) {
+				runs[n-1].last = i
+			} else {
+				runs = append(runs, wetRun{i, i})
+			}
+			if precipSeverity(item.Pty) > precipSeverity(worstPty) {
+				worstPty = item.Pty
+			}
+		}
+		if pop, ok := numericValue(item.Pop); ok && pop > summary.MaxPop {
+			summary.MaxPop = pop
+		}
+	}
+	var precipParts []string
+	halves := [2]bool{}
+	for _, run := range runs {
+		kind := precipWord(items[run.first].Pty)
+		period := models.PrecipPeriod{Kind: kind, Start: items[run.first].Time}
+		startHour, _ := strconv.Atoi(items[run.first].Time[:2])
+		endHour, _ := strconv.Atoi(items[run.last].Time[:2])
+		endHour++
+		untilEnd := run.last == len(items)-1
+		if !untilEnd {
+			period.End = fmt.Sprintf("%02d00", endHour)
+		}
+		fromStart := run.first == 0 && startHour <= 1
+		halves[0] = halves[0] || startHour < 12
+		halves[1] = halves[1] || endHour > 12
+		summary.PrecipPeriods = append(summary.PrecipPeriods, period)
+
+		switch {
+		case fromStart && untilEnd:
+			precipParts = append(precipParts, "하루 종일 "+kind)
+		case untilEnd:
+			precipParts = append(precipParts, fmt.Sprintf("%s부터 %s", hourPhrase(startHour), kind))
+		case fromStart:
+			precipParts = append(precipParts, fmt.Sprintf("%s까지 %s", hourPhrase(endHour), kind))
+		default:
+			precipParts = append(precipParts, fmt.Sprintf("%s부터 %s까지 %s", hourPhrase(startHour), hourPhrase(endHour), kind))
+		}
+	}
+	if len(runs) > 0 {
+		first := summary.PrecipPeriods[0]
+		summary.PrecipKind, summary.PrecipStart, summary.PrecipEnd = first.Kind, first.Start, first.End
+		// 세 번 이상 오락가락하면 시간대를 모두 늘어놓지 않고 "오전·오후 한때"로 줄입니다.
+		if len(runs) > 2 {
+			when := "오전·오후"
+			if !halves[1] {
+				when = "오전"
+			} else if !halves[0] {
+				when = "오후"
+			}
+			precipParts = []string{fmt.Sprintf("%s 한때 %s", when, precipWord(worstPty))}
+		}
+		parts = append(parts, precipParts...)
+	} else if trend := skyTrend(items); trend != "" {
+		// 2. 비가 없으면 하늘 상태 흐름
+		summary.SkyTrend = trend
+		parts = append(parts, trend)
+	}
+
+	// 3. 기온 범위
+	minItem, maxItem := tempExtremes(items)
+	if minItem != nil {
+		low, _ := numericValue(minItem.Tmp)
+		high, _ := numericValue(maxItem.Tmp)
+		summary.MinTemp, summary.MaxTemp = &low, &high
+		parts = append(parts, fmt.Sprintf("최고 %.0f℃ 최저 %.0f℃", high, low))
+
+		// 4. 어제와 비교 (관측/예보 이력)
+		if yesterdayLow, yesterdayHigh, ok := tempRange(yesterdayTemps(locationKey(homeLocation()), date)); ok {
+			minDiff, maxDiff := low-yesterdayLow, high-yesterdayHigh
+			summary.YesterdayMinDiff, summary.YesterdayDiff = &minDiff, &maxDiff
+			parts = append(parts, yesterdayPhrase(minDiff, maxDiff))
+		}
+	}
+
+	// 5. 우산
+	switch {
+	case len(runs) > 0 || summary.MaxPop >= 60:
+		parts = append(parts, "우산 필수")
+	case summary.MaxPop >= 30:
+		parts = append(parts, "작은 우산 챙기세요")
+	}
+
+	summary.Text = fmt.Sprintf("%s은 %s", dayLabel, strings.Join(parts, ", "))
+	return summary
+}
+
+// "어제보다 3℃ 낮아요" (반올림해 1도 미만이면 "어제와 비슷해요")
+func diffPhrase(diff float64) string {
+	rounded := math.Round(diff)
+	switch {
+	case rounded >= 1:
+		return fmt.Sprintf("어제보다 %.0f℃ 높아요", rounded)
+	case rounded <= -1:
+		return fmt.Sprintf("어제보다 %.0f℃ 낮아요", -rounded)
+	default:
+		return "어제와 비슷해요"
+	}
+}
+
+// 요청의 day 파라미터(today/tomorrow)에 맞는 요약을 만듭니다.
+func summaryForRequest(r *http.Request) (models.ForecastSummary, error) {
+	allWeather, err := fetchAndCacheWeather()
+	if err != nil {
+		return models.ForecastSummary{}, err
+	}
+	day, label := time.Now().In(seoul), "오늘"
+	if r.URL.Query().Get("day") == "tomorrow" {
+		day, label = day.AddDate(0, 0, 1), "내일"
+	}
+	date := day.Format("20060102")
+	return summarizeDay(forecastForDate(allWeather, date), date, label), nil
+}
+
+// GetSummary는 요약 문장을 HTML 조각으로 반환합니다.
+func GetSummary(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	summary, err := summaryForRequest(r)
+	if err != nil {
+		http.Error(w, "날씨 정보를 가져올 수 없습니다.", http.StatusInternalServerError)
+		return
+	}
+	renderFragment(w, "forecast-summary", summary.Text)
+}
+
+// GetSummaryJSON은 요약 문장과 그 근거 값을 JSON으로 반환합니다.
+func GetSummaryJSON(w http.ResponseWriter, r *http.Request) {
+	summary, err := summaryForRequest(r)
+	if err != nil {
+		http.Error(w, "날씨 정보를 가져올 수 없습니다.", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
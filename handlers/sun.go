@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 해가 지평선에 걸린 것으로 보는 고도. 대기 굴절과 태양 반지름을 감안한 -0.833°,
+// 시민 박명은 -6°입니다.
+const (
+	sunriseAltitude  = -0.833
+	civilTwilightAlt = -6.0
+)
+
+// homeCoordinates는 집의 위경도입니다. WEATHER_LAT/WEATHER_LON이 없으면 격자 중심을 씁니다.
+func homeCoordinates() (float64, float64) {
+	lat, errLat := strconv.ParseFloat(os.Getenv("WEATHER_LAT"), 64)
+	lon, errLon := strconv.ParseFloat(os.Getenv("WEATHER_LON"), 64)
+	if errLat == nil && errLon == nil {
+		return lat, lon
+	}
+	home := homeLocation()
+	return gridToLatLon(home.Nx, home.Ny)
+}
+
+func julianToTime(jd float64) time.Time {
+	seconds := (jd - 2440587.5) * 86400
+	return time.Unix(0, int64(seconds*float64(time.Second))).In(seoul)
+}
+
+// sunTimes는 천문 계산식(일출 방정식)으로 하루의 남중 시각과, 해가 altitude 고도를 지나는 시각을 구합니다.
+// 백야/극야처럼 그 고도를 지나지 않으면 ok가 false입니다. 오차는 1~2분 정도입니다.
+func sunTimes(day time.Time, lat, lon, altitude float64) (rise, noon, set time.Time, ok bool) {
+	const deg = math.Pi / 180
+	y, m, d := day.Date()
+	jdNoon := float64(time.Date(y, m, d, 12, 0, 0, 0, time.UTC).Unix())/86400 + 2440587.5
+	n := math.Round(jdNoon - 2451545.0)
+
+	meanSolarTime := n - lon/360
+	anomaly := math.Mod(357.5291+0.98560028*meanSolarTime, 360)
+	center := 1.9148*math.Sin(anomaly*deg) + 0.02*math.Sin(2*anomaly*deg) + 0.0003*math.Sin(3*anomaly*deg)
+	eclipticLon := math.Mod(anomaly+center+180+102.9372, 360)
+	transit := 2451545.0 + meanSolarTime + 0.0053*math.Sin(anomaly*deg) - 0.0069*math.Sin(2*eclipticLon*deg)
+
+	declination := math.Asin(math.Sin(eclipticLon*deg) * math.Sin(23.4397*deg))
+	cosHourAngle := (math.Sin(altitude*deg) - math.Sin(lat*deg)*math.Sin(declination)) /
+		(math.Cos(lat*deg) * math.Cos(declination))
+	noon = julianToTime(transit)
+	if cosHourAngle < -1 || cosHourAngle > 1 {
+		return time.Time{}, noon, time.Time{}, false
+	}
+	hourAngle := math.Acos(cosHourAngle) / deg
+	return julianToTime(transit - hourAngle/360), noon, julianToTime(transit + hourAngle/360), true
+}
+
+// sunInfo는 날짜의 일출/일몰/시민 박명을 모읍니다. now가 그날이면 낮인지와 테마도 채웁니다.
+func sunInfo(day time.Time, lat, lon float64, now time.Time) (models.SunInfo, error) {
+	info := models.SunInfo{Date: day.Format("20060102"), Lat: lat, Lon: lon}
+	sunrise, noon, sunset, ok := sunTimes(day, lat, lon, sunriseAltitude)
+	if !ok {
+		return info, fmt.Errorf("%s에는 해가 뜨거나 지지 않습니다", info.Date)
+	}
+	info.Sunrise, info.SolarNoon, info.Sunset = sunrise, noon, sunset
+	if dawn, _, dusk, ok := sunTimes(day, lat, lon, civilTwilightAlt); ok {
+		info.CivilDawn, info.CivilDusk = dawn, dusk
+	}
+	info.DayLength = int(sunset.Sub(sunrise).Minutes())
+	info.IsDay = !now.Before(sunrise) && now.Before(sunset)
+	info.Theme = "dark"
+	if info.IsDay {
+		info.Theme = "light"
+	}
+	return info, nil
+}
+
+// 예보 칸마다 계산하지 않도록 날짜별 결과를 기억해 둡니다.
+var sunCache = struct {
+	days  map[string]models.SunInfo
+	mutex sync.Mutex
+}{days: make(map[string]models.SunInfo)}
+
+// homeSunInfo는 집 위치의 그날 해 정보입니다.
+func homeSunInfo(day time.Time) (models.SunInfo, bool) {
+	lat, lon := homeCoordinates()
+	key := fmt.Sprintf("%.4f,%.4f|%s", lat, lon, day.Format("20060102"))
+	sunCache.mutex.Lock()
+	defer sunCache.mutex.Unlock()
+	if info, ok := sunCache.days[key]; ok {
+		return info, true
+	}
+	info, err := sunInfo(day, lat, lon, time.Time{})
+	if err != nil {
+		return info, false
+	}
+	if len(sunCache.days) > 60 {
+		sunCache.days = make(map[string]models.SunInfo)
+	}
+	sunCache.days[key] = info
+	return info, true
+}
+
+// isNight는 t가 집 위치에서 해가 진 뒤이거나 뜨기 전인지 알려줍니다.
+func isNight(t time.Time) bool {
+	info, ok := homeSunInfo(t.In(seoul))
+	if !ok {
+		return t.Hour() >= 20 || t.Hour() < 6
+	}
+	return t.Before(info.Sunrise) || !t.Before(info.Sunset)
+}
+
+// skyIcon은 예보 칸에 보여줄 아이콘입니다. 강수가 있으면 강수 형태, 없으면 낮/밤에 맞는 하늘 상태입니다.
+func skyIcon(item models.WeatherItem) string {
+	if item.Pty != "none" && item.Pty != "" {
+		return item.Pty
+	}
+	// 밤 시간대의 맑음/구름많음은 그 시각의 달 위상 아이콘으로 보여줍니다.
+	if t, err := slotTime(item); err == nil && isNight(t) {
+		_, moon := moonPhaseAt(t)
+		switch item.Sky {
+		case "🌤":
+			return moon
+		case "🌥":
+			return moon + "☁"
+		}
+	}
+	return item.Sky
+}
+
+// "11시간 13분"
+func dayLengthText(minutes int) string {
+	return fmt.Sprintf("%d시간 %d분", minutes/60, minutes%60)
+}
+
+// 요청의 date(20060102)를 읽습니다. 없으면 오늘입니다.
+func astronomyDay(r *http.Request) (time.Time, error) {
+	day := time.Now().In(seoul)
+	if date := r.URL.Query().Get("date"); date != "" {
+		parsed, err := time.ParseInLocation("20060102", date, seoul)
+		if err != nil {
+			return day, fmt.Errorf("date는 20060102 형식이어야 합니다")
+		}
+		day = parsed
+	}
+	return day, nil
+}
+
+func sunInfoFromRequest(r *http.Request) (models.SunInfo, error) {
+	day, err := astronomyDay(r)
+	if err != nil {
+		return models.SunInfo{}, err
+	}
+	lat, lon := homeCoordinates()
+	return sunInfo(day, lat, lon, time.Now().In(seoul))
+}
+
+// GetSunJSON은 집 위치의 일출/일몰/시민 박명/낮 길이를 JSON으로 반환합니다. (?date=20060102)
+func GetSunJSON(w http.ResponseWriter, r *http.Request) {
+	info, err := sunInfoFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+// GetSunInfo는 머리글에 넣을 일출/일몰 조각을 반환합니다. data-theme으로 화면 테마를 알려줍니다.
+func GetSunInfo(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	info, err := sunInfoFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	renderFragment(w, "sun-info", struct {
+		Theme, CivilDawn, CivilDusk, SolarNoon, Sunrise, Sunset, DayLength string
+	}{
+		Theme:     info.Theme,
+		CivilDawn: info.CivilDawn.Format("15:04"),
+		CivilDusk: info.CivilDusk.Format("15:04"),
+		SolarNoon: info.SolarNoon.Format("15:04"),
+		Sunrise:   info.Sunrise.Format("15:04"),
+		Sunset:    info.Sunset.Format("15:04"),
+		DayLength: dayLengthText(info.DayLength),
+	})
+}
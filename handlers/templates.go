@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// htmx가 끼워 넣는 HTML 조각은 handlers/templates의 html/template 파일로 그립니다.
+// 값은 문맥에 맞게 자동으로 이스케이프되므로 뉴스·일정처럼 외부에서 온 글도 그대로 넘기면 됩니다.
+// TEMPLATE_DIR을 지정하면 그 디렉터리의 *.html이 같은 이름의 {{define}}을 덮어씁니다.
+// 파일이 바뀌면 서버를 다시 띄우지 않아도 다음 요청부터 반영됩니다.
+
+//go:embed templates/*.html
+var embeddedTemplates embed.FS
+
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+var templateCache = struct {
+	tmpl  *template.Template
+	stamp string
+	mutex sync.Mutex
+}{}
+
+func templateDir() string { return os.Getenv("TEMPLATE_DIR") }
+
+// templateStamp는 덮어쓰기 디렉터리의 파일 목록과 수정 시각으로 만든 표식입니다. 바뀌면 다시 읽습니다.
+func templateStamp(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.html"))
+	var b strings.Builder
+	b.WriteString(dir)
+	for _, path := range matches {
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(&b, "|%s@%d", filepath.Base(path), info.ModTime().UnixNano())
+		}
+	}
+	return b.String()
+}
+
+// loadTemplates는 내장 템플릿을 읽고, dir이 있으면 그 위에 덮어씁니다.
+func loadTemplates(dir string) (*template.Template, error) {
+	tmpl, err := template.New("").Funcs(templateFuncs).ParseFS(embeddedTemplates, "templates/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("내장 템플릿 파싱 실패: %v", err)
+	}
+	if dir == "" {
+		return tmpl, nil
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil || len(matches) == 0 {
+		return tmpl, nil
+	}
+	if tmpl, err = tmpl.ParseFiles(matches...); err != nil {
+		return nil, fmt.Errorf("%s 템플릿 파싱 실패: %v", dir, err)
+	}
+	return tmpl, nil
+}
+
+// templates는 현재 템플릿 묶음을 돌려줍니다. 덮어쓴 파일에 문법 오류가 있으면 직전 템플릿을 계속 씁니다.
+func templates() (*template.Template, error) {
+	dir := templateDir()
+	stamp := templateStamp(dir)
+
+	templateCache.mutex.Lock()
+	defer templateCache.mutex.Unlock()
+	if templateCache.tmpl != nil && templateCache.stamp == stamp {
+		return templateCache.tmpl, nil
+	}
+	tmpl, err := loadTemplates(dir)
+	if err != nil {
+		if templateCache.tmpl != nil {
+			log.Printf("템플릿 다시 읽기 실패, 이전 템플릿 사용: %v", err)
+			return templateCache.tmpl, nil
+		}
+		return nil, err
+	}
+	if templateCache.tmpl != nil {
+		log.Printf("템플릿을 다시 읽었습니다 (%s)", time.Now().In(seoul).Format("15:04:05"))
+	}
+	templateCache.tmpl, templateCache.stamp = tmpl, stamp
+	return tmpl, nil
+}
+
+func executeTemplate(w io.Writer, name string, data any) error {
+	tmpl, err := templates()
+	if err != nil {
+		return err
+	}
+	return tmpl.ExecuteTemplate(w, name, data)
+}
+
+// renderFragment는 템플릿 name을 data로 그려 응답합니다.
+// 중간에 실패해도 반쪽짜리 HTML이 나가지 않도록 버퍼에 먼저 그립니다.
+func renderFragment(w http.ResponseWriter, name string, data any) {
+	var buf bytes.Buffer
+	if err := executeTemplate(&buf, name, data); err != nil {
+		log.Printf("템플릿 %s 렌더링 실패: %v", name, err)
+		http.Error(w, "화면을 그릴 수 없습니다.", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	buf.WriteTo(w)
+}
+
+// safeLink는 http/https 주소만 통과시킵니다. javascript: 같은 주소는 빈 문자열이 됩니다.
+func safeLink(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	return u.String()
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import "github.com/mseongj/weather-reminder/models"
+
+// tileView는 templates/tile.html의 "tile" 조각이 그리는 예보 칸 하나입니다.
+// 오늘/내일 이후/구성원 예보가 같은 조각을 쓰고, 칸마다 필요한 항목만 채웁니다.
+type tileView struct {
+	Class     string // weather 외에 붙일 클래스
+	Label     string // 통근 시간대 라벨
+	Change    string // 예보 변경 내용
+	Icon      string
+	TempClass string
+	Tmp       string
+	Diff      *tempDiffMark
+	FeelsLike *feelsLikeNote
+	PopLabel  string
+	Pop       string
+	Humidity  string // 비어 있으면 습도 줄을 빼고 그립니다.
+	Wind      *windMark
+	Time      string
+}
+
+func newTile(item models.WeatherItem) tileView {
+	return tileView{
+		Icon:      skyIcon(item),
+		TempClass: feelsTempClass(item),
+		Tmp:       item.Tmp,
+		FeelsLike: feelsLikeLine(item),
+		PopLabel:  "강수확률",
+		Pop:       item.Pop,
+		Wind:      windLine(item),
+		Time:      formatTime(item.Time),
+	}
+}
@@ -34,11 +34,18 @@ type WeatherCache struct {
 	Data      []models.WeatherItem
 	ExpiresAt time.Time
 	mutex     sync.RWMutex
+
+	lastUsed time.Time // weatherCachesMutex로 보호합니다
 }
 
+// 격자별 예보 캐시 최대 개수. 넘으면 집을 제외하고 가장 오래 쓰이지 않은 격자를 지웁니다.
+const maxWeatherCaches = 32
+
 var (
-	weatherCache = &WeatherCache{}
-	httpClient   = &http.Client{
+	// 격자 좌표("nx,ny")별 예보 캐시
+	weatherCaches      = make(map[string]*WeatherCache)
+	weatherCachesMutex sync.Mutex
+	httpClient         = &http.Client{
 		Timeout: 10 * time.Second,
 		Transport: &http.Transport{
 			MaxIdleConns:        100,
@@ -57,6 +64,21 @@ func getAPIKEY(isNews bool) string {
 	return os.Getenv("API_KEY")
 }
 
+// 대시보드의 기본 위치. WEATHER_NX/WEATHER_NY로 바꿀 수 있습니다.
+func homeLocation() models.Location {
+	loc := models.Location{Name: "우리 집", Nx: 77, Ny: 131}
+	if name := os.Getenv("WEATHER_LOCATION_NAME"); name != "" {
+		loc.Name = name
+	}
+	if nx, err := strconv.Atoi(os.Getenv("WEATHER_NX")); err == nil {
+		loc.Nx = nx
+	}
+	if ny, err := strconv.Atoi(os.Getenv("WEATHER_NY")); err == nil {
+		loc.Ny = ny
+	}
+	return loc
+}
+
 func getBaseDateTime() (string, string) {
 	now := time.Now().Add(-10 * time.Minute)
 	hour := now.Hour()
@@ -87,7 +109,7 @@ func getBaseDateTime() (string, string) {
 	return baseDate, baseTime
 }
 
-func getWeatherData() ([]models.WeatherItemToReturn, error) {
+func getWeatherData(loc models.Location) ([]models.WeatherItemToReturn, error) {
 	metrics := RequestMetrics{StartTime: time.Now()}
 	baseDate, baseTime := getBaseDateTime()
 	apiUrl := fmt.Sprintf(
@@ -95,8 +117,8 @@ func getWeatherData() ([]models.WeatherItemToReturn, error) {
 		"https://apihub.kma.go.kr/api/typ02/openApi/VilageFcstInfoService_2.0/getVilageFcst?pageNo=1&numOfRows=900&dataType=JSON&base_date=%s&base_time=%s&nx=%d&ny=%d&authKey=%s",
 		baseDate,
 		baseTime, 
-		77,
-		131,
+		loc.Nx,
+		loc.Ny,
 		getAPIKEY(false),
 	)
 
@@ -128,6 +150,8 @@ func getWeatherData() ([]models.WeatherItemToReturn, error) {
 	var result []models.WeatherItemToReturn
 	for _, item := range weatherResp.Response.Body.Items.Item {
 		result = append(result, models.WeatherItemToReturn{
+			BaseDate: item.BaseDate,
+			BaseTime: item.BaseTime,
 			Date:     item.FcstDate,
 			Time:     item.FcstTime,
 			Category: item.Category,
@@ -164,14 +188,18 @@ func parseCategory(category, value string) string {
 	}
 }
 
-func WeatherDataParse() ([]models.WeatherItem, error) {
-	rawData, err := getWeatherData()
+func WeatherDataParse(loc models.Location) ([]models.WeatherItem, error) {
+	rawData, err := getWeatherData(loc)
 	if err != nil {
 		return nil, fmt.Errorf("getWeatherData()에서 error: %v", err)
 	}
 	if len(rawData) == 0 {
 		return nil, fmt.Errorf("getWeatherData()가 비어있음")
 	}
+	// 예보는 발표 시각별로 이력에 쌓아 둡니다. 저장에 실패해도 화면 표시는 계속합니다.
+	if err := recordForecastHistory(loc, rawData); err != nil {
+		log.Printf("예보 이력 저장 실패: %v", err)
+	}
 
 	grouped := make(map[string]*models.WeatherItem, len(rawData)/5)
 	for _, item := range rawData {
@@ -185,11 +213,18 @@ func WeatherDataParse() ([]models.WeatherItem, error) {
 		case "TMP": grouped[key].Tmp = item.Value + "℃"
 		case "POP": grouped[key].Pop = item.Value + "%"
 		case "REH": grouped[key].Humidity = item.Value + "%"
+		case "TMN": grouped[key].Tmn = item.Value + "℃"
+		case "TMX": grouped[key].Tmx = item.Value + "℃"
+		case "PCP": grouped[key].Pcp = item.Value
+		case "SNO": grouped[key].Sno = item.Value
+		case "WSD": grouped[key].Wsd = item.Value + "m/s"
+		case "VEC": grouped[key].Vec = item.Value
 		}
 	}
 
 	result := make([]models.WeatherItem, 0, len(grouped))
 	for _, weather := range grouped {
+		applyFeelsLike(weather)
 		result = append(result, *weather)
 	}
 	return result, nil
@@ -199,6 +234,24 @@ func formatTime(timeStr string) string {
 	return fmt.Sprintf("%s시", timeStr[:2])
 }
 
+// 예보 칸의 날짜/시각(20060102, 1500)을 서울 기준 time.Time으로 변환합니다.
+func slotTime(item models.WeatherItem) (time.Time, error) {
+	return time.ParseInLocation("200601021504", item.Date+item.Time, seoul)
+}
+
+// "23℃", "60%", "3.2m/s" 같은 표시용 문자열에서 숫자만 꺼냅니다.
+func numericValue(s string) (float64, bool) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(s, "℃")
+	s = strings.TrimSuffix(s, "%")
+	s = strings.TrimSuffix(s, "m/s")
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
+
 func getTempClass(tempStr string) string {
 	tempStr = strings.TrimSuffix(tempStr, "℃")
 	temp, err := strconv.Atoi(tempStr)
@@ -222,7 +275,42 @@ func getNextForecastTime() time.Time {
 	return time.Date(now.Year(), now.Month(), now.Day()+1, 2, 10, 0, 0, time.Local)
 }
 
-func getFromCache() ([]models.WeatherItem, bool) {
+func locationKey(loc models.Location) string {
+	return fmt.Sprintf("%d,%d", loc.Nx, loc.Ny)
+}
+
+func cacheFor(loc models.Location) *WeatherCache {
+	weatherCachesMutex.Lock()
+	defer weatherCachesMutex.Unlock()
+	key := locationKey(loc)
+	if weatherCaches[key] == nil {
+		if len(weatherCaches) >= maxWeatherCaches {
+			evictWeatherCache()
+		}
+		weatherCaches[key] = &WeatherCache{}
+	}
+	weatherCaches[key].lastUsed = time.Now()
+	return weatherCaches[key]
+}
+
+// 호출 시 weatherCachesMutex를 잡고 있어야 합니다.
+func evictWeatherCache() {
+	home := locationKey(homeLocation())
+	oldest := ""
+	for key, cache := range weatherCaches {
+		if key == home {
+			continue
+		}
+		if oldest == "" || cache.lastUsed.Before(weatherCaches[oldest].lastUsed) {
+			oldest = key
+		}
+	}
+	if oldest != "" {
+		delete(weatherCaches, oldest)
+	}
+}
+
+func getFromCache(weatherCache *WeatherCache) ([]models.WeatherItem, bool) {
 	weatherCache.mutex.RLock()
 	defer weatherCache.mutex.RUnlock()
 	if time.Now().Before(weatherCache.ExpiresAt) {
@@ -231,26 +319,43 @@ func getFromCache() ([]models.WeatherItem, bool) {
 	return nil, false
 }
 
-func setCache(data []models.WeatherItem) {
+// 만료 여부와 상관없이 마지막으로 받은 예보 (변경 감지용)
+func previousCache(weatherCache *WeatherCache) []models.WeatherItem {
+	weatherCache.mutex.RLock()
+	defer weatherCache.mutex.RUnlock()
+	return weatherCache.Data
+}
+
+func setCache(weatherCache *WeatherCache, data []models.WeatherItem) {
 	weatherCache.mutex.Lock()
 	defer weatherCache.mutex.Unlock()
 	weatherCache.Data = data
 	weatherCache.ExpiresAt = getNextForecastTime()
 }
 
+// fetchAndCacheWeather는 기본 위치(homeLocation)의 예보를 반환합니다.
 func fetchAndCacheWeather() ([]models.WeatherItem, error) {
-    if cachedData, ok := getFromCache(); ok {
+    return fetchAndCacheWeatherAt(homeLocation())
+}
+
+func fetchAndCacheWeatherAt(loc models.Location) ([]models.WeatherItem, error) {
+    weatherCache := cacheFor(loc)
+    if cachedData, ok := getFromCache(weatherCache); ok {
         log.Printf("캐시된 날씨 데이터 사용 (만료 시간: %v)", weatherCache.ExpiresAt)
         return cachedData, nil
     }
 
-    result, err := WeatherDataParse()
+    result, err := WeatherDataParse(loc)
     if err != nil {
         log.Printf("날씨 데이터 가져오기 실패: %v", err)
         return nil, err
     }
 
-    setCache(result)
+    previous := previousCache(weatherCache)
+    setCache(weatherCache, result)
+    if len(previous) > 0 {
+        recordForecastChanges(loc, previous, result)
+    }
     log.Printf("새로운 날씨 데이터 캐시 저장 (만료 시간: %v)", weatherCache.ExpiresAt)
     return result, nil
 }
@@ -336,101 +441,56 @@ func GetFutureWeather(w http.ResponseWriter, r *http.Request) {
 }
 
 func renderTodayWeather(w http.ResponseWriter, items []models.WeatherItem, tomorrowPreview []models.WeatherItem) {
-	fmt.Fprint(w, `<div class="weather-grid">`)
-	if len(items) > 0 {
+	changed := forecastChanges.bySlot(locationKey(homeLocation()))
+	diffs := yesterdayDiffs(locationKey(homeLocation()), append(append([]models.WeatherItem(nil), items...), tomorrowPreview...))
+	tiles := func(items []models.WeatherItem) []tileView {
+		var result []tileView
 		for _, item := range items {
-			displayIcon := item.Sky
-			if item.Pty != "none" {
-				displayIcon = item.Pty
-			}
-			tempClass := getTempClass(item.Tmp)
-			fmt.Fprintf(w, `
-							<div class="weather">
-									<p class="sky-status">%s</p>
-									<p class="temp %s">%s</p>
-									<p class="rain-chance">강수확률: %s</p>
-									<p class="humidity">습도: %s</p>
-									<p class="time">%s</p>
-							</div>`,
-				displayIcon, tempClass, item.Tmp, item.Pop, item.Humidity, formatTime(item.Time))
+			tile := newTile(item)
+			tile.Change = changeNote(changed, item)
+			tile.Diff = yesterdayDiffMark(diffs, item)
+			tile.Humidity = item.Humidity
+			result = append(result, tile)
 		}
+		return result
 	}
+	renderFragment(w, "weather-today", struct {
+		Today, Tomorrow []tileView
+	}{tiles(items), tiles(tomorrowPreview)})
+}
 
-	if len(tomorrowPreview) > 0 {
-		style := ""
-    if len(items) > 0 {
-     	style = `style="margin-top: 15px;"`
-		}
-    fmt.Fprint(w, `<h3 class="date-title grid-full-width" style="margin-top: 15px;">내일 새벽 (1-6시)</h3>`, style)
-
-    for _, item := range tomorrowPreview {
-    	displayIcon := item.Sky
-      if item.Pty != "none" {
-      	displayIcon = item.Pty
-      }
-      tempClass := getTempClass(item.Tmp)
-      fmt.Fprintf(w, `
-      	<div class="weather">
-        <p class="sky-status">%s</p>
-        <p class="temp %s">%s</p>
-        <p class="rain-chance">강수확률: %s</p>
-        <p class="humidity">습도: %s</p>
-        <p class="time">%s</p>
-        </div>`,
-        displayIcon, tempClass, item.Tmp, item.Pop, item.Humidity, formatTime(item.Time))
-      }
-    }
-	fmt.Fprint(w, `</div>`)
+// futureDay는 "weather-future" 템플릿의 날짜 묶음 하나입니다.
+type futureDay struct {
+	Title   string
+	Summary *dailySummary
+	Tiles   []tileView
 }
 
 func renderFutureWeather(w http.ResponseWriter, dates []string, data map[string][]models.WeatherItem) {
-    for i, date := range dates {
-			items := data[date]
-      sort.Slice(items, func(i, j int) bool {
-      	return items[i].Time < items[j].Time
+	changed := forecastChanges.bySlot(locationKey(homeLocation()))
+	var days []futureDay
+	for i, date := range dates {
+		items := data[date]
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].Time < items[j].Time
 		})
 
-		formattedDate := fmt.Sprintf("%s월 %s일", date[4:6], date[6:8])
-    fmt.Fprintf(w, `<div class="date-group">
-    	<h3 class="date-title">%s</h3>
-      <div class="weather-grid">`, formattedDate)
-
+		day := futureDay{
+			Title:   fmt.Sprintf("%s월 %s일", date[4:6], date[6:8]),
+			Summary: dailyHeader(dailyAggregate(date, items)),
+		}
 		for _, item := range items {
-			shouldDisplay := false
-
 			timeInt, _ := strconv.Atoi(item.Time[:2])
-
-      if i == 0 {
-      	// "내일" (i == 0) 날씨: 6시(> 5) 이후부터 1시간 간격으로 표시
-        // ⭐️ 2. (0시-5시 제외) 조건을 추가
-        if timeInt > 5 { 
-        	shouldDisplay = true
-        }
-      } else {
-      	// "모레" (i > 0) 날씨: 2시간 간격으로 표시
-        if timeInt % 2 == 0 { 
-        	shouldDisplay = true
-        }
-      }
-
-      // ⭐️ 3. shouldDisplay가 true일 때만 렌더링
-			if shouldDisplay {
-      	displayIcon := item.Sky
-				if item.Pty != "none" {
-					displayIcon = item.Pty
-				}
-      
-				tempClass := getTempClass(item.Tmp)
-				fmt.Fprintf(w, `
-					<div class="weather">
-					<p class="sky-status">%s</p>
-					<p class="temp %s">%s</p>
-					<p class="rain-chance">강수: %s</p>
-					<p class="time">%s</p>
-					</div>`,
-					displayIcon, tempClass, item.Tmp, item.Pop, formatTime(item.Time))
-      }
-    }
-    fmt.Fprint(w, `</div></div>`)
-  }
-}
\ No newline at end of file
+			// "내일" (i == 0)은 6시 이후 1시간 간격, "모레" (i > 0)는 2시간 간격으로 표시
+			if (i == 0 && timeInt <= 5) || (i > 0 && timeInt%2 != 0) {
+				continue
+			}
+			tile := newTile(item)
+			tile.Change = changeNote(changed, item)
+			tile.PopLabel = "강수"
+			day.Tiles = append(day.Tiles, tile)
+		}
+		days = append(days, day)
+	}
+	renderFragment(w, "weather-future", days)
+}
@@ -0,0 +1,439 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// VAPID 키 (RFC 8292). base64url(패딩 없음)로 저장합니다.
+type vapidKeys struct {
+	PublicKey  string `json:"publicKey"`  // P-256 비압축 공개키 65바이트
+	PrivateKey string `json:"privateKey"` // P-256 개인키 스칼라 32바이트
+
+	signer *ecdsa.PrivateKey
+}
+
+type pushStore struct {
+	keys          *vapidKeys
+	subscriptions []models.PushSubscription
+	mutex         sync.Mutex
+}
+
+var webPush = &pushStore{}
+
+var b64 = base64.RawURLEncoding
+
+func vapidKeysPath() string         { return dataPath("vapid.json") }
+func pushSubscriptionsPath() string { return dataPath("push_subscriptions.json") }
+
+// VAPID_PUBLIC_KEY/VAPID_PRIVATE_KEY 환경변수가 있으면 사용하고,
+// 없으면 data/vapid.json을 읽거나 새로 만들어 저장합니다.
+func loadVAPIDKeys() (*vapidKeys, error) {
+	keys := &vapidKeys{
+		PublicKey:  os.Getenv("VAPID_PUBLIC_KEY"),
+		PrivateKey: os.Getenv("VAPID_PRIVATE_KEY"),
+	}
+	if keys.PublicKey == "" || keys.PrivateKey == "" {
+		if err := readJSONFile(vapidKeysPath(), keys); err != nil {
+			return nil, err
+		}
+	}
+
+	if keys.PublicKey == "" || keys.PrivateKey == "" {
+		priv, err := ecdh.P256().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("VAPID 키 생성 실패: %v", err)
+		}
+		keys.PublicKey = b64.EncodeToString(priv.PublicKey().Bytes())
+		keys.PrivateKey = b64.EncodeToString(priv.Bytes())
+		if err := writeJSONFile(vapidKeysPath(), keys); err != nil {
+			return nil, err
+		}
+		log.Println("새 VAPID 키를 생성했습니다")
+	}
+
+	pub, err := b64.DecodeString(keys.PublicKey)
+	if err != nil || len(pub) != 65 {
+		return nil, fmt.Errorf("VAPID 공개키 형식이 잘못되었습니다")
+	}
+	d, err := b64.DecodeString(keys.PrivateKey)
+	if err != nil || len(d) != 32 {
+		return nil, fmt.Errorf("VAPID 개인키 형식이 잘못되었습니다")
+	}
+	keys.signer = &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(pub[1:33]),
+			Y:     new(big.Int).SetBytes(pub[33:65]),
+		},
+		D: new(big.Int).SetBytes(d),
+	}
+	return keys, nil
+}
+
+// 키와 구독 목록을 처음 사용할 때 불러옵니다. 호출 시 s.mutex를 잡고 있어야 합니다.
+func (s *pushStore) ensureLoaded() error {
+	if s.keys != nil {
+		return nil
+	}
+	keys, err := loadVAPIDKeys()
+	if err != nil {
+		return err
+	}
+	var subs []models.PushSubscription
+	if err := readJSONFile(pushSubscriptionsPath(), &subs); err != nil {
+		return err
+	}
+	for i := range subs {
+		if subs[i].RecipientID == "" {
+			subs[i].RecipientID = defaultRecipientID
+		}
+	}
+	s.keys = keys
+	s.subscriptions = subs
+	return nil
+}
+
+// 호출 시 s.mutex를 잡고 있어야 합니다.
+func (s *pushStore) save() {
+	if err := writeJSONFile(pushSubscriptionsPath(), s.subscriptions); err != nil {
+		log.Printf("푸시 구독 저장 실패: %v", err)
+	}
+}
+
+func (s *pushStore) subscribe(sub models.PushSubscription) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.ensureLoaded(); err != nil {
+		return err
+	}
+	if sub.RecipientID == "" {
+		sub.RecipientID = defaultRecipientID
+	}
+	// 같은 endpoint로 다시 구독하면 키와 수신자만 갱신합니다.
+	for i := range s.subscriptions {
+		if s.subscriptions[i].Endpoint == sub.Endpoint {
+			s.subscriptions[i].Keys = sub.Keys
+			s.subscriptions[i].RecipientID = sub.RecipientID
+			s.save()
+			return nil
+		}
+	}
+	sub.CreatedAt = time.Now()
+	s.subscriptions = append(s.subscriptions, sub)
+	s.save()
+	return nil
+}
+
+func (s *pushStore) unsubscribe(endpoint string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.ensureLoaded(); err != nil {
+		log.Printf("푸시 구독 불러오기 실패: %v", err)
+		return
+	}
+	kept := s.subscriptions[:0]
+	for _, sub := range s.subscriptions {
+		if sub.Endpoint != endpoint {
+			kept = append(kept, sub)
+		}
+	}
+	s.subscriptions = kept
+	s.save()
+}
+
+// hkdf는 RFC 5869 HKDF-SHA256 (Extract + Expand, 출력 32바이트 이하)입니다.
+func hkdf(salt, ikm, info []byte, length int) []byte {
+	extract := hmac.New(sha256.New, salt)
+	extract.Write(ikm)
+	prk := extract.Sum(nil)
+
+	expand := hmac.New(sha256.New, prk)
+	expand.Write(info)
+	expand.Write([]byte{0x01})
+	return expand.Sum(nil)[:length]
+}
+
+// encryptPushPayload는 RFC 8291 (aes128gcm) 형식으로 메시지를 암호화합니다.
+func encryptPushPayload(sub models.PushSubscription, payload []byte) ([]byte, error) {
+	uaPublic, err := b64.DecodeString(sub.Keys.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("p256dh 디코딩 실패: %v", err)
+	}
+	authSecret, err := b64.DecodeString(sub.Keys.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("auth 디코딩 실패: %v", err)
+	}
+	uaKey, err := ecdh.P256().NewPublicKey(uaPublic)
+	if err != nil {
+		return nil, fmt.Errorf("브라우저 공개키가 잘못되었습니다: %v", err)
+	}
+
+	// 메시지마다 새 임시 키쌍과 salt를 사용합니다.
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+	ecdhSecret, err := asPrivate.ECDH(uaKey)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublic...)
+	keyInfo = append(keyInfo, asPublic...)
+	ikm := hkdf(authSecret, ecdhSecret, keyInfo, 32)
+	cek := hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	// 레코드가 하나뿐이므로 마지막 레코드 구분자(0x02)만 붙입니다.
+	plaintext := append(append([]byte(nil), payload...), 0x02)
+	ciphertext := gcm.Seal(nil, nonce, plaintext, nil)
+
+	var body bytes.Buffer
+	body.Write(salt)
+	binary.Write(&body, binary.BigEndian, uint32(4096))
+	body.WriteByte(byte(len(asPublic)))
+	body.Write(asPublic)
+	body.Write(ciphertext)
+	return body.Bytes(), nil
+}
+
+// vapidAuthorization은 푸시 서비스 origin을 audience로 하는 ES256 JWT를 만듭니다.
+func vapidAuthorization(keys *vapidKeys, endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("endpoint 파싱 실패: %v", err)
+	}
+	subject := os.Getenv("VAPID_SUBJECT")
+	if subject == "" {
+		subject = "mailto:admin@localhost"
+	}
+
+	header := b64.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`))
+	claims, _ := json.Marshal(map[string]interface{}{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": subject,
+	})
+	signingInput := header + "." + b64.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, keys.signer, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("VAPID 서명 실패: %v", err)
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+
+	jwt := signingInput + "." + b64.EncodeToString(sig)
+	return fmt.Sprintf("vapid t=%s, k=%s", jwt, keys.PublicKey), nil
+}
+
+// errSubscriptionGone은 푸시 서비스가 구독 만료(404/410)를 알려왔을 때 반환합니다.
+var errSubscriptionGone = fmt.Errorf("만료된 푸시 구독")
+
+func sendPush(keys *vapidKeys, sub models.PushSubscription, payload []byte) error {
+	body, err := encryptPushPayload(sub, payload)
+	if err != nil {
+		return err
+	}
+	auth, err := vapidAuthorization(keys, sub.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("푸시 요청 생성 실패: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", "43200")
+	req.Header.Set("Urgency", "normal")
+	req.Header.Set("Authorization", auth)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("푸시 요청 실패: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return errSubscriptionGone
+	case resp.StatusCode >= 300:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("푸시 서비스 응답 코드 %d: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
+
+// sendPushTo는 recipientID 수신자의 구독 브라우저로 알림을 보내고, 만료된 구독은 삭제합니다.
+func sendPushTo(recipientID string, n models.Notification) {
+	webPush.mutex.Lock()
+	if err := webPush.ensureLoaded(); err != nil {
+		webPush.mutex.Unlock()
+		log.Printf("푸시 구독 불러오기 실패: %v", err)
+		return
+	}
+	keys := webPush.keys
+	var subs []models.PushSubscription
+	for _, sub := range webPush.subscriptions {
+		if sub.RecipientID == recipientID {
+			subs = append(subs, sub)
+		}
+	}
+	webPush.mutex.Unlock()
+
+	payload, err := json.Marshal(n)
+	if err != nil {
+		log.Printf("알림 직렬화 실패: %v", err)
+		return
+	}
+	for _, sub := range subs {
+		err := sendPush(keys, sub, payload)
+		if err == errSubscriptionGone {
+			log.Printf("만료된 푸시 구독 삭제: %s", sub.Endpoint)
+			webPush.unsubscribe(sub.Endpoint)
+			continue
+		}
+		if err != nil {
+			log.Printf("푸시 전송 실패 (%s): %v", sub.Endpoint, err)
+		}
+	}
+}
+
+// 브라우저들이 쓰는 푸시 서비스 호스트. 서버가 이 주소로만 POST하도록 다른 endpoint는 받지 않습니다.
+var pushServiceHosts = []string{
+	"fcm.googleapis.com",        // Chrome, Edge, Samsung Internet
+	"push.services.mozilla.com", // Firefox
+	"notify.windows.com",        // 구형 Edge
+	"push.apple.com",            // Safari
+}
+
+// pushHostAllowed는 host가 알려진 푸시 서비스이거나 PUSH_ALLOWED_HOSTS(쉼표 구분)에 있는지 확인합니다.
+func pushHostAllowed(host string) bool {
+	allowed := pushServiceHosts
+	for _, h := range strings.Split(os.Getenv("PUSH_ALLOWED_HOSTS"), ",") {
+		if h = strings.TrimSpace(h); h != "" {
+			allowed = append(allowed, h)
+		}
+	}
+	host = strings.ToLower(host)
+	for _, h := range allowed {
+		h = strings.ToLower(h)
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePushSubscription은 보낼 때가 아니라 구독할 때 endpoint와 키를 확인합니다.
+func validatePushSubscription(sub models.PushSubscription) error {
+	u, err := url.Parse(sub.Endpoint)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return fmt.Errorf("endpoint는 https URL이어야 합니다.")
+	}
+	if !pushHostAllowed(u.Hostname()) {
+		return fmt.Errorf("알 수 없는 푸시 서비스입니다: %s", u.Hostname())
+	}
+	if sub.Keys.P256dh == "" || sub.Keys.Auth == "" {
+		return fmt.Errorf("구독 키가 없습니다.")
+	}
+	uaPublic, err := b64.DecodeString(sub.Keys.P256dh)
+	if err != nil {
+		return fmt.Errorf("p256dh 디코딩 실패: %v", err)
+	}
+	if _, err := ecdh.P256().NewPublicKey(uaPublic); err != nil {
+		return fmt.Errorf("브라우저 공개키가 잘못되었습니다: %v", err)
+	}
+	// RFC 8291: auth 비밀값은 16바이트입니다.
+	if authSecret, err := b64.DecodeString(sub.Keys.Auth); err != nil || len(authSecret) != 16 {
+		return fmt.Errorf("auth는 16바이트 base64url 값이어야 합니다.")
+	}
+	return nil
+}
+
+// GetVAPIDPublicKey는 브라우저가 구독할 때 쓰는 applicationServerKey를 반환합니다.
+func GetVAPIDPublicKey(w http.ResponseWriter, r *http.Request) {
+	webPush.mutex.Lock()
+	err := webPush.ensureLoaded()
+	keys := webPush.keys
+	webPush.mutex.Unlock()
+	if err != nil {
+		log.Printf("VAPID 키 불러오기 실패: %v", err)
+		http.Error(w, "푸시 설정을 불러올 수 없습니다.", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"publicKey": keys.PublicKey})
+}
+
+// SubscribePush는 브라우저의 PushSubscription JSON(+recipientId)을 받아 저장합니다.
+func SubscribePush(w http.ResponseWriter, r *http.Request) {
+	var sub models.PushSubscription
+	if err := json.NewDecoder(io.LimitReader(r.Body, 8192)).Decode(&sub); err != nil {
+		http.Error(w, "잘못된 구독 정보입니다.", http.StatusBadRequest)
+		return
+	}
+	if err := validatePushSubscription(sub); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := webPush.subscribe(sub); err != nil {
+		log.Printf("푸시 구독 저장 실패: %v", err)
+		http.Error(w, "구독을 저장할 수 없습니다.", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+// UnsubscribePush는 {"endpoint": "..."}로 지정한 구독을 삭제합니다.
+func UnsubscribePush(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Endpoint string `json:"endpoint"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, 8192)).Decode(&req); err != nil || req.Endpoint == "" {
+		http.Error(w, "endpoint가 필요합니다.", http.StatusBadRequest)
+		return
+	}
+	webPush.unsubscribe(req.Endpoint)
+	w.WriteHeader(http.StatusNoContent)
+}
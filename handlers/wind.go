@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 16방위 풍향. 기상청 풍향(VEC)은 바람이 불어오는 방향의 각도(북=0, 시계 방향)입니다.
+var compassPoints = []string{
+	"북", "북북동", "북동", "동북동", "동", "동남동", "남동", "남남동",
+	"남", "남남서", "남서", "서남서", "서", "서북서", "북서", "북북서",
+}
+
+func compassDirection(deg float64) string {
+	deg = math.Mod(math.Mod(deg, 360)+360, 360)
+	return compassPoints[int((deg+11.25)/22.5)%16]
+}
+
+// 보퍼트 풍력 계급 (상한 풍속 m/s, 이름)
+var beaufortScale = []struct {
+	Max  float64
+	Name string
+}{
+	{0.3, "고요"},
+	{1.6, "실바람"},
+	{3.4, "남실바람"},
+	{5.5, "산들바람"},
+	{8.0, "건들바람"},
+	{10.8, "흔들바람"},
+	{13.9, "된바람"},
+	{17.2, "센바람"},
+	{20.8, "큰바람"},
+	{24.5, "큰센바람"},
+	{28.5, "노대바람"},
+	{32.7, "왕바람"},
+}
+
+// beaufort는 풍속(m/s)의 보퍼트 계급과 이름을 돌려줍니다.
+func beaufort(wsd float64) (int, string) {
+	for level, b := range beaufortScale {
+		if wsd < b.Max {
+			return level, b.Name
+		}
+	}
+	return len(beaufortScale), "싹쓸바람"
+}
+
+// "북북동풍 3.2m/s (남실바람)". 풍향을 모르면 방향은 빼고 씁니다.
+func windText(item models.WeatherItem) string {
+	wsd, ok := numericValue(item.Wsd)
+	if !ok {
+		return ""
+	}
+	_, name := beaufort(wsd)
+	if deg, ok := numericValue(item.Vec); ok && wsd >= 0.3 {
+		return fmt.Sprintf("%s풍 %.1fm/s (%s)", compassDirection(deg), wsd, name)
+	}
+	return fmt.Sprintf("%.1fm/s (%s)", wsd, name)
+}
+
+// windMark는 예보 칸에 넣는 바람 표시입니다. 화살표는 바람이 불어가는 쪽을 가리킵니다.
+type windMark struct {
+	Level int // 보퍼트 계급
+	Speed string
+	Title string
+	Arrow bool
+	Deg   int // ↓는 북풍(0°)일 때의 모양이므로 풍향만큼 돌립니다.
+}
+
+func windLine(item models.WeatherItem) *windMark {
+	wsd, ok := numericValue(item.Wsd)
+	if !ok {
+		return nil
+	}
+	level, _ := beaufort(wsd)
+	mark := &windMark{Level: level, Speed: fmt.Sprintf("%.1fm/s", wsd), Title: windText(item)}
+	if deg, ok := numericValue(item.Vec); ok && wsd >= 0.3 {
+		mark.Arrow, mark.Deg = true, int(math.Round(deg))
+	}
+	return mark
+}
+
+// 강풍주의보(육상 풍속 14m/s)·경보(21m/s) 기준으로 센 바람을 알려줍니다.
+func checkWind(items []models.WeatherItem) (models.Reminder, bool) {
+	_, maxItem := extremesBy(items, func(item models.WeatherItem) (float64, bool) { return numericValue(item.Wsd) })
+	if maxItem == nil {
+		return models.Reminder{}, false
+	}
+	wsd, _ := numericValue(maxItem.Wsd)
+	if wsd < 14 {
+		return models.Reminder{}, false
+	}
+
+	severity := models.SeverityWarning
+	if wsd >= 21 {
+		severity = models.SeverityCritical
+	}
+	t, _ := slotTime(*maxItem)
+	return models.Reminder{
+		Kind:     "wind",
+		Severity: severity,
+		Title:    "강풍 주의",
+		Message:  fmt.Sprintf("%d시 %s, 간판·화분 같은 날릴 수 있는 물건을 조심하세요", t.Hour(), windText(*maxItem)),
+		Start:    t,
+		End:      t.Add(time.Hour),
+	}, true
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mseongj/weather-reminder/models"
+)
+
+// 하루의 절반 이상 기록이 있어야 최저/최고를 비교합니다.
+const minYesterdayHours = 12
+
+// 지난 날짜의 기온은 거의 바뀌지 않으므로 이력을 다시 읽는 횟수를 줄입니다.
+const yesterdayCacheTTL = 30 * time.Minute
+
+type pastTemps struct {
+	temps      map[string]float64 // 시각(1500) → 기온
+	computedAt time.Time
+}
+
+var pastTempCache = struct {
+	entries map[string]pastTemps
+	mutex   sync.Mutex
+}{entries: make(map[string]pastTemps)}
+
+// dayTemps는 지난 하루의 시각별 기온을 찾습니다.
+// 초단기실황 관측값(T1H)을 먼저 쓰고, 관측이 없는 시각은 그 시각에 가장 가까운 발표의 예보 기온(TMP)으로 채웁니다.
+func dayTemps(grid string, day time.Time) map[string]float64 {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, seoul)
+	key := grid + "|" + start.Format("20060102")
+
+	pastTempCache.mutex.Lock()
+	cached, ok := pastTempCache.entries[key]
+	pastTempCache.mutex.Unlock()
+	if ok && time.Since(cached.computedAt) < yesterdayCacheTTL {
+		return cached.temps
+	}
+
+	end := start.Add(23 * time.Hour)
+	temps := make(map[string]float64)
+
+	// 예보: 같은 시각이면 나중 발표(실제에 더 가까운 예보)를 씁니다.
+	latestBase := make(map[string]time.Time)
+	err := scanForecasts(grid, start.AddDate(0, 0, -3), end, func(record models.ForecastRecord) {
+		if record.FcstTime.Before(start) || record.FcstTime.After(end) {
+			return
+		}
+		tmp, ok := numericValue(record.Values["TMP"])
+		if !ok {
+			return
+		}
+		slot := record.FcstTime.In(seoul).Format("1504")
+		if base, seen := latestBase[slot]; seen && !record.BaseTime.After(base) {
+			return
+		}
+		latestBase[slot] = record.BaseTime
+		temps[slot] = tmp
+	})
+	if err != nil {
+		log.Printf("예보 이력 읽기 실패: %v", err)
+	}
+
+	observations, err := observationsBetween(grid, start, end)
+	if err != nil {
+		log.Printf("관측 이력 읽기 실패: %v", err)
+	}
+	for _, record := range observations {
+		if tmp, ok := numericValue(record.Values["T1H"]); ok {
+			temps[record.Time.Format("1504")] = tmp
+		}
+	}
+
+	pastTempCache.mutex.Lock()
+	for k, entry := range pastTempCache.entries {
+		if time.Since(entry.computedAt) >= yesterdayCacheTTL {
+			delete(pastTempCache.entries, k)
+		}
+	}
+	pastTempCache.entries[key] = pastTemps{temps: temps, computedAt: time.Now()}
+	pastTempCache.mutex.Unlock()
+	return temps
+}
+
+// yesterdayTemps는 date(20060102) 전날의 시각별 기온입니다.
+func yesterdayTemps(grid, date string) map[string]float64 {
+	day, err := time.ParseInLocation("20060102", date, seoul)
+	if err != nil {
+		return nil
+	}
+	return dayTemps(grid, day.AddDate(0, 0, -1))
+}
+
+// 기록이 충분할 때만 최저/최고 기온을 돌려줍니다.
+func tempRange(temps map[string]float64) (low, high float64, ok bool) {
+	if len(temps) < minYesterdayHours {
+		return 0, 0, false
+	}
+	low, high = math.Inf(1), math.Inf(-1)
+	for _, tmp := range temps {
+		low = math.Min(low, tmp)
+		high = math.Max(high, tmp)
+	}
+	return low, high, true
+}
+
+// yesterdayDiffs는 예보 칸마다 어제 같은 시각과의 기온 차이를 계산합니다. (키: 날짜+시각)
+func yesterdayDiffs(grid string, items []models.WeatherItem) map[string]float64 {
+	diffs := make(map[string]float64)
+	byDate := make(map[string]map[string]float64)
+	for _, item := range items {
+		tmp, ok := numericValue(item.Tmp)
+		if !ok {
+			continue
+		}
+		if _, loaded := byDate[item.Date]; !loaded {
+			byDate[item.Date] = yesterdayTemps(grid, item.Date)
+		}
+		if before, ok := byDate[item.Date][item.Time]; ok {
+			diffs[item.Date+item.Time] = tmp - before
+		}
+	}
+	return diffs
+}
+
+// tempDiffMark는 기온 옆에 붙이는 "▲3" 표시입니다.
+type tempDiffMark struct {
+	Class string // warmer, colder, same
+	Mark  string
+	Title string
+}
+
+// 어제 같은 시각과의 차이 표시. 어제 기록이 없으면 nil입니다.
+func yesterdayDiffMark(diffs map[string]float64, item models.WeatherItem) *tempDiffMark {
+	diff, ok := diffs[item.Date+item.Time]
+	if !ok {
+		return nil
+	}
+	rounded := math.Round(diff)
+	switch {
+	case rounded >= 1:
+		return &tempDiffMark{"warmer", fmt.Sprintf("▲%.0f", rounded), fmt.Sprintf("어제 같은 시각보다 %.0f℃ 높아요", rounded)}
+	case rounded <= -1:
+		return &tempDiffMark{"colder", fmt.Sprintf("▼%.0f", -rounded), fmt.Sprintf("어제 같은 시각보다 %.0f℃ 낮아요", -rounded)}
+	default:
+		return &tempDiffMark{"same", "-", "어제 같은 시각과 비슷해요"}
+	}
+}
+
+func diffSign(diff float64) int {
+	switch rounded := math.Round(diff); {
+	case rounded >= 1:
+		return 1
+	case rounded <= -1:
+		return -1
+	}
+	return 0
+}
+
+// 요약 문장에 넣는 어제 대비 표현. 최저/최고가 같은 방향이면 최고기온 차이만 말합니다.
+// "어제보다 3℃ 높아요", "어제보다 최저기온은 2℃ 낮고 최고기온은 1℃ 높아요"
+func yesterdayPhrase(minDiff, maxDiff float64) string {
+	if diffSign(minDiff) == diffSign(maxDiff) {
+		return diffPhrase(maxDiff)
+	}
+	low := map[int]string{
+		1:  fmt.Sprintf("최저기온은 %.0f℃ 높고", math.Round(minDiff)),
+		-1: fmt.Sprintf("최저기온은 %.0f℃ 낮고", -math.Round(minDiff)),
+		0:  "최저기온은 비슷하고",
+	}[diffSign(minDiff)]
+	high := map[int]string{
+		1:  fmt.Sprintf("최고기온은 %.0f℃ 높아요", math.Round(maxDiff)),
+		-1: fmt.Sprintf("최고기온은 %.0f℃ 낮아요", -math.Round(maxDiff)),
+		0:  "최고기온은 비슷해요",
+	}[diffSign(maxDiff)]
+	return fmt.Sprintf("어제보다 %s %s", low, high)
+}
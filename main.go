@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/mseongj/weather-reminder/handlers"
 	"github.com/mseongj/weather-reminder/routes"
 )
 
@@ -35,6 +36,8 @@ func enableCORS(next http.Handler) http.Handler {
 
 func main() {
 	router := routes.SetupRoutes()
+	handlers.StartScheduler()
+	handlers.StartMQTT()
 	fmt.Println("Server is running on http://localhost:8080")
 	http.ListenAndServe(":8080", enableCORS(router))
 }
\ No newline at end of file
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// LeadTimeStats는 예보 선행 시간(발표 후 몇 시간 뒤를 예보했는지) 구간별 정확도입니다.
+// 표본이 없는 값은 nil입니다.
+type LeadTimeStats struct {
+	Label    string `json:"label"` // "0~6시간"
+	MinHours int    `json:"minHours"`
+	MaxHours int    `json:"maxHours"` // 이 값 미만
+
+	TmpSamples int      `json:"tmpSamples"`
+	TmpMAE     *float64 `json:"tmpMae"`  // 기온 평균 절대 오차 (℃)
+	TmpBias    *float64 `json:"tmpBias"` // 기온 평균 오차 (예보 - 관측, 양수면 예보가 더 높음)
+
+	PopSamples int      `json:"popSamples"`
+	Brier      *float64 `json:"brier"` // 강수확률 Brier 점수 (0이 완벽, 낮을수록 좋음)
+
+	Hits             int      `json:"hits"`             // 강수 예보 O, 실제 강수 O
+	Misses           int      `json:"misses"`           // 강수 예보 X, 실제 강수 O
+	FalseAlarms      int      `json:"falseAlarms"`      // 강수 예보 O, 실제 강수 X
+	CorrectNegatives int      `json:"correctNegatives"` // 강수 예보 X, 실제 강수 X
+	HitRate          *float64 `json:"hitRate"`          // 실제 비 온 시간 중 예보한 비율
+	FalseAlarmRatio  *float64 `json:"falseAlarmRatio"`  // 비 예보 중 빗나간 비율
+}
+
+// CalibrationBin은 강수확률 구간별로 실제 비가 온 비율입니다. 잘 맞는 예보라면 두 값이 비슷합니다.
+type CalibrationBin struct {
+	From              int      `json:"from"` // %
+	To                int      `json:"to"`
+	Samples           int      `json:"samples"`
+	MeanForecast      *float64 `json:"meanForecast"`      // 구간 안 예보 강수확률 평균 (%)
+	ObservedFrequency *float64 `json:"observedFrequency"` // 실제 강수 비율 (%)
+}
+
+// AccuracyReport는 한 격자의 예보 정확도 통계입니다.
+type AccuracyReport struct {
+	Grid        string           `json:"grid"`
+	From        time.Time        `json:"from"`
+	To          time.Time        `json:"to"`
+	Overall     LeadTimeStats    `json:"overall"`
+	Leads       []LeadTimeStats  `json:"leads"`
+	Calibration []CalibrationBin `json:"calibration"`
+}
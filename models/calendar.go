@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// CalendarEvent는 ICS 파일에서 읽은 일정 한 건(반복 일정은 회차마다 한 건)입니다.
+type CalendarEvent struct {
+	UID      string    `json:"uid"`
+	Summary  string    `json:"summary"`
+	Location string    `json:"location"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	AllDay   bool      `json:"allDay"`
+	// GEO 속성이 있으면 위경도
+	Lat *float64 `json:"lat,omitempty"`
+	Lon *float64 `json:"lon,omitempty"`
+}
+
+// EventWeather는 일정과 그 시각/장소의 예보를 짝지은 결과입니다.
+type EventWeather struct {
+	Event    CalendarEvent `json:"event"`
+	Location Location      `json:"location"` // 장소를 격자로 찾지 못하면 집
+	Weather  *WeatherItem  `json:"weather"`  // 예보 범위 밖이면 nil
+	Outdoor  bool          `json:"outdoor"`  // 야외 일정으로 보이는지
+	Bad      bool          `json:"bad"`      // 비/눈, 높은 강수확률, 한파/폭염
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// ForecastChange는 새 발표 예보가 직전 예보와 달라진 점 하나입니다.
+type ForecastChange struct {
+	ID         string    `json:"id"`
+	Grid       string    `json:"grid"`
+	Kind       string    `json:"kind"` // precip, pop, tmin, tmax
+	Date       string    `json:"date"` // 20261020
+	Message    string    `json:"message"`
+	Old        string    `json:"old"`
+	New        string    `json:"new"`
+	Slots      []string  `json:"slots"` // 영향을 받은 예보 칸 (날짜+시각, "202610201500")
+	DetectedAt time.Time `json:"detectedAt"`
+}
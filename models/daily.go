@@ -0,0 +1,17 @@
+package models
+
+// DailyAggregate는 하루치 시간별 예보를 모은 값입니다.
+type DailyAggregate struct {
+	Date        string   `json:"date"` // 20060102
+	MinTemp     *float64 `json:"minTemp"`
+	MaxTemp     *float64 `json:"maxTemp"`
+	MinFromTMN  bool     `json:"minFromTmn"` // 기상청 일 최저기온(TMN)을 썼는지, false면 시간별 기온 중 최저
+	MaxFromTMX  bool     `json:"maxFromTmx"`
+	Precip      float64  `json:"precip"` // 강수량 합계 (mm)
+	Snow        float64  `json:"snow"`   // 신적설 합계 (cm)
+	MaxPop      float64  `json:"maxPop"`
+	Sky         string   `json:"sky"`                  // 가장 많이 나온 하늘 상태 아이콘
+	PrecipKind  string   `json:"precipKind,omitempty"` // 가장 강한 강수 형태 아이콘
+	PrecipHours int      `json:"precipHours"`
+	Hours       int      `json:"hours"` // 집계에 쓴 예보 칸 수
+}
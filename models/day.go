@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// LunarDate는 한국 음력 날짜입니다.
+type LunarDate struct {
+	Year  int  `json:"year"`
+	Month int  `json:"month"`
+	Day   int  `json:"day"`
+	Leap  bool `json:"leap"` // 윤달
+}
+
+// Holiday는 공휴일 하루입니다. 대체공휴일은 Substitute가 true입니다.
+type Holiday struct {
+	Date       string `json:"date"` // 20060102
+	Name       string `json:"name"`
+	Substitute bool   `json:"substitute,omitempty"`
+}
+
+// DayInfo는 머리글에 보여줄 하루의 달력 정보입니다.
+type DayInfo struct {
+	Date           string    `json:"date"` // 20060102
+	Weekday        string    `json:"weekday"`
+	Lunar          LunarDate `json:"lunar"`
+	Holidays       []Holiday `json:"holidays"`
+	IsHoliday      bool      `json:"isHoliday"` // 일요일 또는 공휴일
+	SolarTerm      string    `json:"solarTerm"` // 지금 속한 절기 (가장 최근에 시작한 절기)
+	SolarTermAt    time.Time `json:"solarTermAt"`
+	SolarTermToday bool      `json:"solarTermToday"` // 그날 절기가 시작하는지
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ForecastRecord는 한 발표 시각(base)에 나온 예보 중 한 시각(fcst)의 값들입니다.
+// Values는 기상청 카테고리(TMP, POP, PTY, SKY, REH, PCP ...)별 원본 값입니다.
+type ForecastRecord struct {
+	Grid      string            `json:"grid"` // "nx,ny"
+	BaseTime  time.Time         `json:"baseTime"`
+	FcstTime  time.Time         `json:"fcstTime"`
+	Values    map[string]string `json:"values"`
+	FetchedAt time.Time         `json:"fetchedAt"`
+}
+
+// ObservationRecord는 초단기실황으로 받은 한 시각의 관측값입니다. (T1H, RN1, REH, PTY, WSD ...)
+type ObservationRecord struct {
+	Grid      string            `json:"grid"`
+	Time      time.Time         `json:"time"`
+	Values    map[string]string `json:"values"`
+	FetchedAt time.Time         `json:"fetchedAt"`
+}
+
+// ForecastHistoryEntry는 특정 시각에 대해 발표 시각마다 어떤 예보가 나왔는지 보여줍니다.
+type ForecastHistoryEntry struct {
+	BaseTime  time.Time         `json:"baseTime"`
+	LeadHours int               `json:"leadHours"` // 발표 시각부터 예보 시각까지 몇 시간 앞선 예보인지
+	Values    map[string]string `json:"values"`
+}
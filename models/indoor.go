@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// IndoorReading은 실내 센서(ESP32 등)가 보낸 온습도 측정값 한 건입니다.
+type IndoorReading struct {
+	SensorID    string    `json:"sensor"`
+	Temperature float64   `json:"temperature"` // ℃
+	Humidity    float64   `json:"humidity"`    // %
+	Time        time.Time `json:"time"`
+}
+
+// IndoorComparison은 실내 최신 측정값과 같은 시각의 바깥 예보를 나란히 놓은 것입니다.
+type IndoorComparison struct {
+	Indoor         IndoorReading `json:"indoor"`
+	OutdoorTemp    *float64      `json:"outdoorTemperature"`
+	OutdoorHumid   *float64      `json:"outdoorHumidity"`
+	Ventilate      bool          `json:"ventilate"`
+	Recommendation string        `json:"recommendation"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// Location은 기상청 동네예보 격자 좌표로 표현한 장소입니다.
+type Location struct {
+	Name string `json:"name"`
+	Nx   int    `json:"nx"`
+	Ny   int    `json:"ny"`
+}
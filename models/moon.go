@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// MoonInfo는 한 장소, 하루의 달 위상과 월출/월몰 시각입니다.
+type MoonInfo struct {
+	Date         string     `json:"date"`         // 20060102
+	Phase        float64    `json:"phase"`        // 0 삭, 0.25 상현, 0.5 망, 0.75 하현
+	PhaseName    string     `json:"phaseName"`    // 삭, 초승달, 상현, ...
+	Emoji        string     `json:"emoji"`        // 🌑 ~ 🌘
+	Illumination float64    `json:"illumination"` // 밝은 면의 비율 (%)
+	Age          float64    `json:"age"`          // 월령 (일)
+	Moonrise     *time.Time `json:"moonrise"`     // 그날 달이 뜨지 않으면 null
+	Moonset      *time.Time `json:"moonset"`
+	AlwaysUp     bool       `json:"alwaysUp,omitempty"`
+	AlwaysDown   bool       `json:"alwaysDown,omitempty"`
+}
+
+// AstronomyInfo는 해와 달 정보를 함께 담습니다.
+type AstronomyInfo struct {
+	Sun  SunInfo  `json:"sun"`
+	Moon MoonInfo `json:"moon"`
+}
@@ -11,9 +11,22 @@ type NaverNewsResponse struct {
 
 // NewsItem은 개별 뉴스 기사 항목입니다.
 type NewsItem struct {
-	Title        string `json:"title"`       // 기사 제목
-	OriginalLink string `json:"originallink"` // 원문 URL
-	Link         string `json:"link"`         // Naver 뉴스 URL
-	Description  string `json:"description"`  // 요약
-	PubDate      string `json:"pubDate"`      // 발행일
+	Title        string   `json:"title"`             // 기사 제목
+	OriginalLink string   `json:"originallink"`      // 원문 URL
+	Link         string   `json:"link"`              // Naver 뉴스 URL
+	Description  string   `json:"description"`       // 요약
+	PubDate      string   `json:"pubDate"`           // 발행일
+	Source       string   `json:"source,omitempty"`  // 피드에서 온 기사의 언론사(피드 제목)
+	Related      []string `json:"related,omitempty"` // 같은 사건으로 묶인 다른 기사 제목
+}
+
+// NewsSection은 뉴스 영역에 따로 보여줄 주제 묶음입니다. (data/news_sections.json)
+type NewsSection struct {
+	ID         string   `json:"id"`         // 캐시 키 (없으면 이름)
+	Name       string   `json:"name"`       // 화면에 보여줄 제목
+	Query      string   `json:"query"`      // Naver 검색어 (없으면 피드만 씁니다)
+	Feeds      []string `json:"feeds"`      // RSS 2.0 / Atom 피드 주소
+	Sort       string   `json:"sort"`       // sim(정확도순) 또는 date(최신순)
+	Count      int      `json:"count"`      // 보여줄 기사 수
+	TTLMinutes int      `json:"ttlMinutes"` // 캐시 유지 시간 (분)
 }
@@ -0,0 +1,27 @@
+package models
+
+// CommuteWindow는 집을 나서거나 돌아오는 시간대입니다.
+type CommuteWindow struct {
+	Label     string `json:"label"`               // "출근", "퇴근" ...
+	Start     string `json:"start"`               // "07:30"
+	End       string `json:"end"`                 // "08:30"
+	Days      []int  `json:"days"`                // 요일 (0=일요일). 비우면 매일
+	Direction string `json:"direction,omitempty"` // outbound(출발지→도착지), return(귀가). 비우면 label로 판단
+}
+
+// 통근 방향
+const (
+	CommuteOutbound = "outbound"
+	CommuteReturn   = "return"
+)
+
+// Profile은 가족 구성원 한 명의 생활 패턴입니다.
+type Profile struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	RecipientID string          `json:"recipientId"` // 알림을 받을 수신자 (비우면 default)
+	Commutes    []CommuteWindow `json:"commutes"`
+	Origin      Location        `json:"origin"`      // 출발지 (비우면 집)
+	Destination Location        `json:"destination"` // 학교/회사 등 (비우면 집과 같음)
+	Reminders   []string        `json:"reminders"`   // 받고 싶은 알림 종류 (umbrella, cold, heat). 비우면 전부
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// PushSubscription은 브라우저 PushManager.subscribe() 결과를 저장하는 구조체입니다.
+type PushSubscription struct {
+	Endpoint    string    `json:"endpoint"`
+	Keys        PushKeys  `json:"keys"`
+	RecipientID string    `json:"recipientId"` // 이 브라우저를 쓰는 수신자 (비우면 default)
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// PushKeys는 메시지 암호화에 쓰이는 브라우저 쪽 키입니다. (base64url)
+type PushKeys struct {
+	P256dh string `json:"p256dh"`
+	Auth   string `json:"auth"`
+}
+
+// Notification은 구독 중인 브라우저로 보내는 알림 내용입니다.
+// 서비스 워커(public/sw.js)가 이 JSON을 받아 알림을 띄웁니다.
+type Notification struct {
+	Title    string `json:"title"`
+	Body     string `json:"body"`
+	Tag      string `json:"tag,omitempty"` // 같은 tag의 알림은 브라우저에서 덮어씁니다
+	URL      string `json:"url,omitempty"` // 알림을 눌렀을 때 열 주소
+	Severity string `json:"severity"`
+}
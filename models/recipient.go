@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Recipient는 알림을 받는 가족 구성원과 그 사람의 수신 설정입니다.
+type Recipient struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Channels    []string `json:"channels"`    // 받을 채널 (push, log)
+	QuietStart  string   `json:"quietStart"`  // 방해 금지 시작 "23:00" (비우면 방해 금지 없음)
+	QuietEnd    string   `json:"quietEnd"`    // 방해 금지 종료 "07:00"
+	MinSeverity string   `json:"minSeverity"` // 이 심각도 미만의 알림은 받지 않음 (info, warning, critical)
+	Digest      bool     `json:"digest"`      // true면 알림을 모아서 하루 한 번 보냄
+	DigestTime  string   `json:"digestTime"`  // 요약을 보낼 시각 "07:30"
+}
+
+// QueuedNotification은 방해 금지 시간이나 요약 모드 때문에 보류된 알림입니다.
+type QueuedNotification struct {
+	RecipientID  string       `json:"recipientId"`
+	Notification Notification `json:"notification"`
+	QueuedAt     time.Time    `json:"queuedAt"`
+	Digest       bool         `json:"digest"` // 요약 시각까지 보류된 알림인지
+}
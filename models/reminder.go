@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// 알림 심각도
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Reminder는 예보를 규칙으로 평가해 만들어진 알림 한 건입니다.
+type Reminder struct {
+	ID        string    `json:"id"`                  // 종류+그 날씨가 처음 시작된 시각으로 만든 고유 키 (같은 알림 중복 방지용)
+	ProfileID string    `json:"profileId,omitempty"` // 특정 구성원의 통근 시간대에 대한 알림이면 그 프로필
+	Kind      string    `json:"kind"`                // umbrella, cold, heat ...
+	Severity  string    `json:"severity"`            // info, warning, critical
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	Start     time.Time `json:"start"` // 해당 날씨가 시작되는 시각
+	End       time.Time `json:"end"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RouteLeg는 경로 예보의 한 지점(출발지 출발, 도착지 도착 등)입니다.
+type RouteLeg struct {
+	Label    string       `json:"label"` // "출발", "도착", "귀가 출발", "귀가 도착"
+	Location Location     `json:"location"`
+	Time     time.Time    `json:"time"`
+	Weather  *WeatherItem `json:"weather"` // 해당 시각 예보가 없으면 nil
+}
+
+// RouteForecast는 출발지와 도착지 예보를 합친 통근 경로 예보입니다.
+type RouteForecast struct {
+	Legs       []RouteLeg `json:"legs"`
+	MaxPop     float64    `json:"maxPop"`     // 가는 길, 도착지 체류, 돌아오는 길 전체에서 가장 높은 강수확률
+	PrecipKind string     `json:"precipKind"` // 경로 중 예보된 가장 나쁜 강수 형태 (없으면 빈 문자열)
+	MinTemp    *float64   `json:"minTemp"`
+	MaxTemp    *float64   `json:"maxTemp"`
+}
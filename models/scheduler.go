@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// JobInfo는 /api/jobs 응답에 포함되는 예약 작업 정보입니다.
+type JobInfo struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Spec        string     `json:"spec"`    // cron 표현식 (Asia/Seoul 기준)
+	LastRun     *time.Time `json:"lastRun"` // 마지막 실행 시각 (없으면 null)
+	NextRun     time.Time  `json:"nextRun"` // 다음 실행 예정 시각
+	LastError   string     `json:"lastError,omitempty"`
+	Running     bool       `json:"running"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// ForecastSummary는 하루치 예보를 한 문장으로 요약한 결과입니다.
+type ForecastSummary struct {
+	Date             string         `json:"date"` // 20060102
+	Text             string         `json:"text"` // "오늘은 오후 3시부터 비, 최고 18℃ 최저 9℃, 우산 필수"
+	PrecipKind       string         `json:"precipKind,omitempty"`
+	PrecipStart      string         `json:"precipStart,omitempty"`   // 첫 강수 시작 시각 (1500)
+	PrecipEnd        string         `json:"precipEnd,omitempty"`     // 첫 강수가 그치는 시각 (2100), 하루 끝까지 이어지면 비어 있음
+	PrecipPeriods    []PrecipPeriod `json:"precipPeriods,omitempty"` // 비/눈이 이어지는 시간대마다 하나
+	MinTemp          *float64       `json:"minTemp"`
+	MaxTemp          *float64       `json:"maxTemp"`
+	MaxPop           float64        `json:"maxPop"`
+	SkyTrend         string         `json:"skyTrend,omitempty"`
+	YesterdayDiff    *float64       `json:"yesterdayDiff"`    // 최고기온의 어제 대비 차이 (℃)
+	YesterdayMinDiff *float64       `json:"yesterdayMinDiff"` // 최저기온의 어제 대비 차이 (℃)
+}
+
+// PrecipPeriod는 하루 중 비/눈이 끊이지 않고 이어지는 시간대 하나입니다.
+type PrecipPeriod struct {
+	Kind  string `json:"kind"`          // 비, 눈, 비/눈, 소나기
+	Start string `json:"start"`         // 1500
+	End   string `json:"end,omitempty"` // 그치는 시각, 하루 끝까지 이어지면 비어 있음
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// SunInfo는 한 장소, 하루의 해 뜨고 지는 시각입니다.
+type SunInfo struct {
+	Date      string    `json:"date"` // 20060102
+	Lat       float64   `json:"lat"`
+	Lon       float64   `json:"lon"`
+	CivilDawn time.Time `json:"civilDawn"` // 시민 박명 시작 (해가 지평선 아래 6°)
+	Sunrise   time.Time `json:"sunrise"`
+	SolarNoon time.Time `json:"solarNoon"` // 남중 시각
+	Sunset    time.Time `json:"sunset"`
+	CivilDusk time.Time `json:"civilDusk"` // 시민 박명 끝
+	DayLength int       `json:"dayLengthMinutes"`
+	IsDay     bool      `json:"isDay"` // 요청 시각이 해 뜬 뒤, 해 지기 전인지
+	Theme     string    `json:"theme"` // 화면 테마 (light, dark)
+}
@@ -17,6 +17,7 @@ type WeatherResponse struct {
 					FcstDate  string `json:"fcstDate"`
 					FcstTime  string `json:"fcstTime"`
 					FcstValue string `json:"fcstValue"`
+					ObsrValue string `json:"obsrValue"` // 초단기실황(getUltraSrtNcst) 관측값
 					Nx        int    `json:"nx"`
 					Ny        int    `json:"ny"`
 				} `json:"item"`
@@ -30,6 +31,8 @@ type WeatherResponse struct {
 
 // 리턴 될 변수의 struct
 type WeatherItemToReturn struct {
+	BaseDate string // 발표 일자
+	BaseTime string // 발표 시각
 	Date     string
 	Time     string
 	Category string
@@ -38,13 +41,23 @@ type WeatherItemToReturn struct {
 
 // WeatherItem은 파싱된 날씨 데이터를 담는 구조체입니다.
 type WeatherItem struct {
-	Date     string
-	Time     string
-	Sky      string // 하늘 상태 (맑음, 구름많음, 흐림)
-	Pty      string // 강수 형태 (없음, 비, 눈 등)
-	Tmp      string // 기온 (℃)
-	Pop      string // 강수 확률 (%)
-	Humidity string // 습도 (%)
+	Date       string
+	Time       string
+	Sky        string // 하늘 상태 (맑음, 구름많음, 흐림)
+	Pty        string // 강수 형태 (없음, 비, 눈 등)
+	Tmp        string // 기온 (℃)
+	Pop        string // 강수 확률 (%)
+	Humidity   string // 습도 (%)
+	Tmn        string // 일 최저기온 (℃), 06시 칸에만 있음
+	Tmx        string // 일 최고기온 (℃), 15시 칸에만 있음
+	Pcp        string // 1시간 강수량 원본 값 ("강수없음", "1mm 미만", "3.0mm" ...)
+	Sno        string // 1시간 신적설 원본 값 ("적설없음", "1cm 미만", "2.0cm" ...)
+	Wsd        string // 풍속 (m/s)
+	Vec        string // 풍향 (deg, 바람이 불어오는 방향)
+	FeelsLike  string // 계절에 맞는 체감온도 (℃), 계산할 수 없으면 기온과 같음
+	WindChill  string // 겨울철 바람냉각 체감온도 (℃)
+	HeatIndex  string // 여름철 체감온도 (℃)
+	Discomfort string // 불쾌지수
 }
 
 // 구분	행정구역코드	1단계	2단계	3단계	격자 X	격자 Y	경도(시)	경도(분)	경도(초)	위도(시)	위도(분)	위도(초)	경도(초/100)	위도(초/100)
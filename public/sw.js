@@ -0,0 +1,40 @@
+// 웹 푸시 알림을 받아 표시하는 서비스 워커
+// 서버는 models.Notification 형태의 JSON({title, body, tag, url, severity})을 보냅니다.
+
+self.addEventListener('push', (event) => {
+    let data = { title: '날씨 알림', body: '' };
+    if (event.data) {
+        try {
+            data = event.data.json();
+        } catch (e) {
+            data.body = event.data.text();
+        }
+    }
+
+    event.waitUntil(
+        self.registration.showNotification(data.title, {
+            body: data.body,
+            tag: data.tag,
+            renotify: Boolean(data.tag),
+            requireInteraction: data.severity === 'critical',
+            data: { url: data.url || '/' },
+        })
+    );
+});
+
+self.addEventListener('notificationclick', (event) => {
+    event.notification.close();
+    const url = event.notification.data && event.notification.data.url ? event.notification.data.url : '/';
+
+    // 이미 열린 대시보드 탭이 있으면 그 탭을 앞으로 가져옵니다.
+    event.waitUntil(
+        clients.matchAll({ type: 'window', includeUncontrolled: true }).then((windowClients) => {
+            for (const client of windowClients) {
+                if (new URL(client.url).pathname === url && 'focus' in client) {
+                    return client.focus();
+                }
+            }
+            return clients.openWindow(url);
+        })
+    );
+});
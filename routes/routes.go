@@ -14,6 +14,43 @@ func SetupRoutes() *mux.Router {
 	router.HandleFunc("/getTodayWeather", handlers.GetTodayWeather).Methods("GET")
 	router.HandleFunc("/getFutureWeather", handlers.GetFutureWeather).Methods("GET")
 	router.HandleFunc("/getTopNews", handlers.GetTopNews).Methods("GET")
+	router.HandleFunc("/getSummary", handlers.GetSummary).Methods("GET")
+	router.HandleFunc("/getProfileWeather", handlers.GetProfileWeather).Methods("GET")
+	router.HandleFunc("/getRouteForecast", handlers.GetRouteForecast).Methods("GET")
+	router.HandleFunc("/getCalendarWeather", handlers.GetCalendarWeather).Methods("GET")
+	router.HandleFunc("/getIndoorComparison", handlers.GetIndoorComparison).Methods("GET")
+	router.HandleFunc("/getAccuracyStats", handlers.GetAccuracyStats).Methods("GET")
+
+	// JSON API
+	router.HandleFunc("/api/jobs", handlers.GetJobs).Methods("GET")
+	router.HandleFunc("/api/summary", handlers.GetSummaryJSON).Methods("GET")
+	router.HandleFunc("/api/reminders", handlers.GetReminders).Methods("GET")
+	router.HandleFunc("/api/recipients", handlers.GetRecipients).Methods("GET")
+	router.HandleFunc("/api/recipients", handlers.PutRecipients).Methods("PUT")
+	router.HandleFunc("/api/profiles", handlers.GetProfiles).Methods("GET")
+	router.HandleFunc("/api/profiles", handlers.PutProfiles).Methods("PUT")
+	router.HandleFunc("/api/mqtt", handlers.GetMQTTStatus).Methods("GET")
+	router.HandleFunc("/api/indoor", handlers.GetIndoorReadings).Methods("GET")
+	router.HandleFunc("/api/indoor", handlers.PostIndoorReading).Methods("POST")
+	router.HandleFunc("/api/history/forecasts", handlers.GetForecastHistory).Methods("GET")
+	router.HandleFunc("/api/history/observations", handlers.GetObservationHistory).Methods("GET")
+	router.HandleFunc("/api/accuracy", handlers.GetAccuracyJSON).Methods("GET")
+	router.HandleFunc("/api/changes", handlers.GetForecastChanges).Methods("GET")
+	router.HandleFunc("/api/daily", handlers.GetDailyAggregates).Methods("GET")
+	router.HandleFunc("/api/sun", handlers.GetSunJSON).Methods("GET")
+	router.HandleFunc("/getSunInfo", handlers.GetSunInfo).Methods("GET")
+	router.HandleFunc("/api/astronomy", handlers.GetAstronomyJSON).Methods("GET")
+	router.HandleFunc("/getMoonInfo", handlers.GetMoonInfo).Methods("GET")
+	router.HandleFunc("/api/day", handlers.GetDayInfoJSON).Methods("GET")
+	router.HandleFunc("/getDateHeader", handlers.GetDateHeader).Methods("GET")
+
+	// 웹 푸시 구독
+	router.HandleFunc("/push/vapidPublicKey", handlers.GetVAPIDPublicKey).Methods("GET")
+	router.HandleFunc("/push/subscribe", handlers.SubscribePush).Methods("POST")
+	router.HandleFunc("/push/unsubscribe", handlers.UnsubscribePush).Methods("POST")
+
+	// 캘린더 구독
+	router.HandleFunc("/calendar/weather.ics", handlers.GetCalendarFeed).Methods("GET")
 
 	// 정적 파일 제공을 위한 핸들러 추가
 	// PathPrefix를 사용하여 / 경로 아래의 모든 요청을 처리합니다.